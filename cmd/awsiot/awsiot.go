@@ -27,4 +27,7 @@ var Command = commands.Source(sourceName,
 	commands.WithSourceOptions(
 		commands.WithAliases([]string{"aws-iot"}),
 	),
+	commands.WithGatewayOptions(
+		commands.WithShort("Export gateways by Wireless Gateway ID"),
+	),
 )
@@ -27,4 +27,8 @@ var Command = commands.Source(sourceName,
 	commands.WithDevicesOptions(
 		commands.WithShort("Export devices by DevEUI"),
 	),
+	commands.WithGatewayOptions(
+		commands.WithShort("Export gateways by Gateway EUI"),
+	),
+	commands.WithApplicationSettingsOptions(),
 )
@@ -24,4 +24,7 @@ const sourceName = "firefly"
 // Command represents the firefly source.
 var Command = commands.Source(sourceName,
 	"Export devices from Digimondo's Firefly",
+	commands.WithGatewayOptions(
+		commands.WithShort("Export gateways by Gateway EUI"),
+	),
 )
@@ -21,7 +21,9 @@ import (
 	"github.com/spf13/cobra"
 	"go.thethings.network/lorawan-stack-migrate/cmd/awsiot"
 	"go.thethings.network/lorawan-stack-migrate/cmd/chirpstack"
+	"go.thethings.network/lorawan-stack-migrate/cmd/file"
 	"go.thethings.network/lorawan-stack-migrate/cmd/firefly"
+	"go.thethings.network/lorawan-stack-migrate/cmd/loriot"
 	"go.thethings.network/lorawan-stack-migrate/cmd/ttnv2"
 	"go.thethings.network/lorawan-stack-migrate/cmd/tts"
 	"go.thethings.network/lorawan-stack-migrate/cmd/wanesy"
@@ -82,7 +84,9 @@ func init() {
 	rootCmd.AddCommand(
 		awsiot.Command,
 		chirpstack.Command,
+		file.Command,
 		firefly.Command,
+		loriot.Command,
 		ttnv2.Command,
 		tts.Command,
 		wanesy.Command,
@@ -22,4 +22,7 @@ import (
 const sourceName = "ttnv2"
 
 // Command represents the ttnv2 source.
-var Command = commands.Source(sourceName, "Export devices from TTN V2")
+var Command = commands.Source(sourceName,
+	"Export devices from TTN V2",
+	commands.WithApplicationSettingsOptions(),
+)
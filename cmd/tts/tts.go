@@ -27,4 +27,6 @@ var Command = commands.Source(sourceName,
 	commands.WithSourceOptions(
 		commands.WithAliases([]string{"ttnv3"}),
 	),
+	commands.WithGatewayOptions(),
+	commands.WithApplicationSettingsOptions(),
 )
@@ -25,6 +25,36 @@ import (
 )
 
 func Export(cmd *cobra.Command, args []string, f func(s source.Source, item string) error) error {
+	return withSource(cmd, func(s source.Source) error {
+		var iter iterator.Iterator
+		switch len(args) {
+		case 0:
+			iter = s.Iterator(cmd.Name() == "application" || cmd.Name() == "application-settings")
+		default:
+			iter = iterator.NewListIterator(args)
+		}
+
+		for {
+			item, err := iter.Next()
+			switch err {
+			case nil:
+			case io.EOF:
+				return nil
+			default:
+				return err
+			}
+			if item == "" {
+				continue
+			}
+
+			if err := f(s, item); err != nil {
+				return err
+			}
+		}
+	})
+}
+
+func withSource(cmd *cobra.Command, f func(s source.Source) error) error {
 	s, err := source.NewSource(cmd.Context())
 	if err != nil {
 		return err
@@ -34,32 +64,23 @@ func Export(cmd *cobra.Command, args []string, f func(s source.Source, item stri
 			log.FromContext(cmd.Context()).WithError(err).Fatal("Failed to clean up")
 		}
 	}()
+	return f(s)
+}
 
-	var iter iterator.Iterator
-	switch len(args) {
-	case 0:
-		iter = s.Iterator(cmd.Name() == "application")
-	default:
-		iter = iterator.NewListIterator(args)
+func gatewaySource(s source.Source) (source.GatewaySource, error) {
+	gs, ok := s.(source.GatewaySource)
+	if !ok {
+		return nil, source.ErrGatewaysNotSupported.WithAttributes("source", source.RootConfig.Source())
 	}
+	return gs, nil
+}
 
-	for {
-		item, err := iter.Next()
-		switch err {
-		case nil:
-		case io.EOF:
-			return nil
-		default:
-			return err
-		}
-		if item == "" {
-			continue
-		}
-
-		if err := f(s, item); err != nil {
-			return err
-		}
+func applicationSettingsSource(s source.Source) (source.ApplicationSettingsSource, error) {
+	as, ok := s.(source.ApplicationSettingsSource)
+	if !ok {
+		return nil, source.ErrApplicationSettingsNotSupported.WithAttributes("source", source.RootConfig.Source())
 	}
+	return as, nil
 }
 
 func ExportApplication() CobraRunE {
@@ -75,3 +96,42 @@ func ExportDevices() CobraRunE {
 		return Export(cmd, args, export.FromContext(cmd.Context()).ExportDev)
 	}
 }
+
+func ExportGateways() CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		source.RootConfig.Entity = source.EntityGateways
+		exportGtw := export.FromContext(cmd.Context()).ExportGtw
+
+		if all, _ := cmd.Flags().GetBool("all-gateways"); all && len(args) == 0 {
+			return withSource(cmd, func(s source.Source) error {
+				gs, err := gatewaySource(s)
+				if err != nil {
+					return err
+				}
+				return gs.RangeGateways(exportGtw)
+			})
+		}
+		return Export(cmd, args, func(s source.Source, item string) error {
+			gs, err := gatewaySource(s)
+			if err != nil {
+				return err
+			}
+			return exportGtw(gs, item)
+		})
+	}
+}
+
+func ExportApplicationSettings() CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		source.RootConfig.Entity = source.EntityApplicationSettings
+		exportAppSettings := export.FromContext(cmd.Context()).ExportAppSettings
+
+		return Export(cmd, args, func(s source.Source, item string) error {
+			as, err := applicationSettingsSource(s)
+			if err != nil {
+				return err
+			}
+			return exportAppSettings(as, item)
+		})
+	}
+}
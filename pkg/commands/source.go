@@ -16,11 +16,14 @@ package commands
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
 )
 
 type SourceOptions struct {
-	opts, appOpts, devOpts []Option
+	opts, appOpts, devOpts, gtwOpts, appSettingsOpts []Option
+
+	gateways, applicationSettings bool
 }
 
 // Extend merges respectable fields from src into s.
@@ -28,6 +31,10 @@ func (s *SourceOptions) Extend(src SourceOptions) {
 	s.opts = append(s.opts, src.opts...)
 	s.appOpts = append(s.appOpts, src.appOpts...)
 	s.devOpts = append(s.devOpts, src.devOpts...)
+	s.gtwOpts = append(s.gtwOpts, src.gtwOpts...)
+	s.appSettingsOpts = append(s.appSettingsOpts, src.appSettingsOpts...)
+	s.gateways = s.gateways || src.gateways
+	s.applicationSettings = s.applicationSettings || src.applicationSettings
 }
 
 // WithSourceOptions returns SourceOptions with opts field set to opts.
@@ -51,6 +58,22 @@ func WithDevicesOptions(opts ...Option) SourceOptions {
 	}
 }
 
+// WithGatewayOptions returns SourceOptions that enable the gateway command, with gtwOpts field set to opts.
+func WithGatewayOptions(opts ...Option) SourceOptions {
+	return SourceOptions{
+		gtwOpts:  opts,
+		gateways: true,
+	}
+}
+
+// WithApplicationSettingsOptions returns SourceOptions that enable the application-settings command, with appSettingsOpts field set to opts.
+func WithApplicationSettingsOptions(opts ...Option) SourceOptions {
+	return SourceOptions{
+		appSettingsOpts:     opts,
+		applicationSettings: true,
+	}
+}
+
 // Source returns a new source command.
 func Source(sourceName, short string, opts ...SourceOptions) *cobra.Command {
 	fs, err := source.FlagSet(sourceName)
@@ -73,12 +96,23 @@ func Source(sourceName, short string, opts ...SourceOptions) *cobra.Command {
 	devCmd := Devices(
 		append(defaults, sourceOpts.devOpts...)...,
 	)
+	subcommands := []*cobra.Command{appCmd, devCmd}
+	if sourceOpts.gateways {
+		subcommands = append(subcommands, Gateways(
+			append(defaults, sourceOpts.gtwOpts...)...,
+		))
+	}
+	if sourceOpts.applicationSettings {
+		subcommands = append(subcommands, ApplicationSettings(
+			append(defaults, sourceOpts.appSettingsOpts...)...,
+		))
+	}
 
 	defaults = []Option{
 		WithUse(sourceName + " ..."),
 		WithShort(short),
 		WithPersistentPreRunE(SourcePersistentPreRunE()),
-		WithSubcommands(appCmd, devCmd),
+		WithSubcommands(subcommands...),
 		WithGroupID("sources"),
 	}
 	cmd := New(
@@ -99,6 +133,17 @@ func Application(opts ...Option) *cobra.Command {
 	return New(append(defaultOpts, opts...)...)
 }
 
+// ApplicationSettings returns a new application settings command.
+func ApplicationSettings(opts ...Option) *cobra.Command {
+	defaultOpts := []Option{
+		WithUse("application-settings ..."),
+		WithShort("Export applications with their link and integrations"),
+		WithAliases([]string{"app-settings"}),
+		WithRunE(ExportApplicationSettings()),
+	}
+	return New(append(defaultOpts, opts...)...)
+}
+
 // Devices returns a new devices command.
 func Devices(opts ...Option) *cobra.Command {
 	defaultOpts := []Option{
@@ -110,6 +155,21 @@ func Devices(opts ...Option) *cobra.Command {
 	return New(append(defaultOpts, opts...)...)
 }
 
+// Gateways returns a new gateways command.
+func Gateways(opts ...Option) *cobra.Command {
+	fs := new(pflag.FlagSet)
+	fs.Bool("all-gateways", false, "Export all gateways of the source")
+
+	defaultOpts := []Option{
+		WithUse("gateway ..."),
+		WithShort("Export gateways by Gateway ID"),
+		WithAliases([]string{"gateways", "gtws", "gtw", "g"}),
+		WithFlagSet(fs),
+		WithRunE(ExportGateways()),
+	}
+	return New(append(defaultOpts, opts...)...)
+}
+
 // SourcePersistentPreRunE returns a new function that sets the active source.
 func SourcePersistentPreRunE() CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
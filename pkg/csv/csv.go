@@ -0,0 +1,325 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package csv reads devices from CSV files of sources. Columns are renamed with a column mapping, and
+// records are read one at a time.
+package csv
+
+import (
+	stdcsv "encoding/csv"
+	"io"
+	"maps"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/pflag"
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+const (
+	// DefaultDelimiter is the default field delimiter.
+	DefaultDelimiter = ","
+	// DefaultEncoding is the default character encoding.
+	DefaultEncoding = "utf-8"
+)
+
+var (
+	errInvalidDelimiter  = errors.DefineInvalidArgument("invalid_delimiter", "invalid delimiter `{delimiter}`")
+	errInvalidEncoding   = errors.DefineInvalidArgument("invalid_encoding", "invalid encoding `{encoding}`")
+	errReadColumnMapping = errors.DefineInvalidArgument("read_column_mapping", "read column mapping file `{file}`")
+	errInvalidMapping    = errors.DefineInvalidArgument("invalid_mapping", "invalid column mapping on line {line} of `{file}`")
+	errNoHeader          = errors.DefineInvalidArgument("no_header", "no header in CSV file")
+	errDuplicateColumn   = errors.DefineInvalidArgument("duplicate_column", "duplicate column `{column}`")
+	errMissingColumn     = errors.DefineInvalidArgument("missing_column", "missing column `{column}`")
+	errInvalidRow        = errors.DefineInvalidArgument("invalid_row", "invalid row on line {line}")
+	errInvalidTarget     = errors.DefineInvalidArgument("invalid_target", "invalid decode target `{type}`")
+)
+
+// Config is the configuration of CSV files.
+type Config struct {
+	// Delimiter is the field delimiter. Use `\t` or `tab` for tab separated files.
+	Delimiter string
+	// Encoding is the character encoding of the file, for example `utf-8`, `utf-16le` or `windows-1252`.
+	Encoding string
+	// ColumnMappingPath is the path to a CSV file with the header of the file and the column name to use
+	// on each line.
+	ColumnMappingPath string
+
+	// ColumnMapping maps headers of the file to column names.
+	ColumnMapping map[string]string
+}
+
+// Flags returns the flags of the CSV configuration.
+// The defaults are read from the environment variables with envPrefix.
+func (c *Config) Flags(envPrefix string) *pflag.FlagSet {
+	flags := &pflag.FlagSet{}
+	flags.StringVar(&c.Delimiter,
+		"csv-delimiter",
+		util.EnvString(envPrefix+"CSV_DELIMITER", DefaultDelimiter),
+		"Field delimiter of the CSV file. Use \\t or tab for tab separated files")
+	flags.StringVar(&c.Encoding,
+		"csv-encoding",
+		util.EnvString(envPrefix+"CSV_ENCODING", DefaultEncoding),
+		"Character encoding of the CSV file, for example utf-8, utf-16le or windows-1252")
+	flags.StringVar(&c.ColumnMappingPath,
+		"csv-column-mapping-file",
+		os.Getenv(envPrefix+"CSV_COLUMN_MAPPING_FILE"),
+		`(optional) Path to a file that maps headers of the CSV file to column names, one mapping per line.
+Use this if the headers of the CSV file are renamed`)
+	return flags
+}
+
+// Initialize validates the configuration and reads the column mapping file.
+func (c *Config) Initialize() error {
+	if _, err := c.delimiter(); err != nil {
+		return err
+	}
+	if _, err := c.encoding(); err != nil {
+		return err
+	}
+	if c.ColumnMappingPath != "" {
+		mapping, err := readColumnMapping(c.ColumnMappingPath)
+		if err != nil {
+			return err
+		}
+		c.ColumnMapping = mapping
+	}
+	return nil
+}
+
+func (c Config) delimiter() (rune, error) {
+	switch c.Delimiter {
+	case "":
+		return ',', nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(c.Delimiter)
+	if size != len(c.Delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, errInvalidDelimiter.WithAttributes("delimiter", c.Delimiter)
+	}
+	return r, nil
+}
+
+// encoding returns a decoder to UTF-8. Byte order marks override the encoding.
+func (c Config) encoding() (transform.Transformer, error) {
+	if c.Encoding == "" {
+		return unicode.BOMOverride(unicode.UTF8.NewDecoder()), nil
+	}
+	enc, err := htmlindex.Get(c.Encoding)
+	if err != nil {
+		return nil, errInvalidEncoding.WithAttributes("encoding", c.Encoding).WithCause(err)
+	}
+	return unicode.BOMOverride(enc.NewDecoder()), nil
+}
+
+// readColumnMapping reads a column mapping file. Each line contains a header and a column name,
+// separated by a comma. Lines starting with `#` are ignored.
+func readColumnMapping(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errReadColumnMapping.WithAttributes("file", path).WithCause(err)
+	}
+	defer f.Close()
+
+	r := stdcsv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	mapping := make(map[string]string)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return mapping, nil
+		}
+		if err != nil {
+			return nil, errReadColumnMapping.WithAttributes("file", path).WithCause(err)
+		}
+		if len(record) != 2 || strings.TrimSpace(record[0]) == "" || strings.TrimSpace(record[1]) == "" {
+			line, _ := r.FieldPos(0)
+			return nil, errInvalidMapping.WithAttributes("file", path, "line", line)
+		}
+		mapping[strings.TrimSpace(record[0])] = strings.TrimSpace(record[1])
+	}
+}
+
+// Reader reads records from a CSV file. The first line of the file is the header.
+type Reader struct {
+	r       *stdcsv.Reader
+	columns []string
+}
+
+// NewReader returns a reader for the CSV file. The header is read, and the columns are renamed with the
+// column mapping. An error is returned if any of the required columns is missing.
+func (c Config) NewReader(r io.Reader, required ...string) (*Reader, error) {
+	delimiter, err := c.delimiter()
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := c.encoding()
+	if err != nil {
+		return nil, err
+	}
+	csvReader := stdcsv.NewReader(transform.NewReader(r, decoder))
+	csvReader.Comma = delimiter
+	// Rows may have fewer fields than the header.
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, errNoHeader.New()
+	}
+	if err != nil {
+		return nil, rowError(err)
+	}
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	// folded contains the lowercase column names, so that required columns are matched case-insensitively.
+	folded := make(map[string]bool, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if column, ok := c.ColumnMapping[h]; ok {
+			h = column
+		}
+		if h != "" && seen[h] {
+			return nil, errDuplicateColumn.WithAttributes("column", h)
+		}
+		columns[i], seen[h], folded[strings.ToLower(h)] = h, true, true
+	}
+	for _, column := range required {
+		if !folded[strings.ToLower(column)] {
+			return nil, errMissingColumn.WithAttributes("column", column)
+		}
+	}
+	return &Reader{
+		r:       csvReader,
+		columns: columns,
+	}, nil
+}
+
+// Columns returns the column names of the file.
+func (r *Reader) Columns() []string {
+	return r.columns
+}
+
+// Read reads the next record. Empty lines are skipped. io.EOF is returned at the end of the file.
+func (r *Reader) Read() (*Record, error) {
+	fields, err := r.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, rowError(err)
+	}
+	line, _ := r.r.FieldPos(0)
+	var (
+		values = make(map[string]string, len(r.columns))
+		folded = make(map[string]string, len(r.columns))
+	)
+	for i, column := range r.columns {
+		if column == "" || i >= len(fields) {
+			continue
+		}
+		values[column] = fields[i]
+		// The first column of which the name matches case-insensitively is used.
+		key := strings.ToLower(column)
+		if _, ok := folded[key]; !ok {
+			folded[key] = fields[i]
+		}
+	}
+	return &Record{
+		Line:   line,
+		values: values,
+		folded: folded,
+	}, nil
+}
+
+// rowError converts a parse error to an error with the line number of the record.
+func rowError(err error) error {
+	if parseErr, ok := err.(*stdcsv.ParseError); ok {
+		return errInvalidRow.WithAttributes("line", parseErr.StartLine).WithCause(parseErr.Err)
+	}
+	return err
+}
+
+// Record is a record of a CSV file.
+type Record struct {
+	// Line is the line number of the record in the file.
+	Line   int
+	values map[string]string
+	// folded contains the values by lowercase column name.
+	folded map[string]string
+}
+
+// Get returns the value of the column. Columns are matched case-insensitively if there is no column with
+// the exact name. An empty string is returned if the column is missing.
+func (r *Record) Get(column string) string {
+	if value, ok := r.values[column]; ok {
+		return value
+	}
+	return r.folded[strings.ToLower(column)]
+}
+
+// Values returns the values of the record by column name.
+func (r *Record) Values() map[string]string {
+	return maps.Clone(r.values)
+}
+
+// Error returns err with the line number of the record.
+func (r *Record) Error(err error) error {
+	return errInvalidRow.WithAttributes("line", r.Line).WithCause(err)
+}
+
+// Decode sets the string fields of the struct that v points to. Fields are matched with columns by the
+// name of the `csv` tag, the `json` tag or the field name, in that order. Fields with tag `-` are ignored.
+// Columns are matched as with Get.
+func (r *Record) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errInvalidTarget.WithAttributes("type", rv.Type().String())
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() || field.Type.Kind() != reflect.String {
+			continue
+		}
+		name := columnName(field)
+		if name == "-" {
+			continue
+		}
+		rv.Field(i).SetString(r.Get(name))
+	}
+	return nil
+}
+
+func columnName(field reflect.StructField) string {
+	for _, key := range []string{"csv", "json"} {
+		if tag, ok := field.Tag.Lookup(key); ok {
+			if name, _, _ := strings.Cut(tag, ","); name != "" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
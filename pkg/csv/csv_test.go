@@ -0,0 +1,180 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack-migrate/pkg/csv"
+)
+
+type device struct {
+	DevEUI  string `csv:"devEui"`
+	Name    string `json:"name,omitempty"`
+	Ignored string `csv:"-"`
+	Count   int
+}
+
+func TestReader(t *testing.T) {
+	a := assertions.New(t)
+	r, err := csv.Config{}.NewReader(strings.NewReader("devEui,name,Count\n0102030405060708,dev-1,1\n\n0102030405060709\n"), "devEui")
+	a.So(err, should.BeNil)
+	a.So(r.Columns(), should.Resemble, []string{"devEui", "name", "Count"})
+
+	record, err := r.Read()
+	a.So(err, should.BeNil)
+	a.So(record.Line, should.Equal, 2)
+	var dev device
+	a.So(record.Decode(&dev), should.BeNil)
+	a.So(dev, should.Resemble, device{DevEUI: "0102030405060708", Name: "dev-1"})
+
+	record, err = r.Read()
+	a.So(err, should.BeNil)
+	a.So(record.Line, should.Equal, 4)
+	a.So(record.Get("devEui"), should.Equal, "0102030405060709")
+	a.So(record.Get("name"), should.BeEmpty)
+	a.So(record.Error(io.ErrUnexpectedEOF).Error(), should.ContainSubstring, "line 4")
+
+	_, err = r.Read()
+	a.So(err, should.Equal, io.EOF)
+}
+
+func TestCaseInsensitiveColumns(t *testing.T) {
+	a := assertions.New(t)
+	type session struct {
+		DevEUI  string `csv:"devEui"`
+		NwkSKey string `json:"NwkSKey"`
+		AppSKey string `json:"AppSKey"`
+	}
+	r, err := csv.Config{}.NewReader(
+		strings.NewReader("DevEUI,nwkSKey,appSKey,APPSKEY\n0102030405060708,01,02,03\n"),
+		"devEui",
+	)
+	a.So(err, should.BeNil)
+	record, err := r.Read()
+	a.So(err, should.BeNil)
+	var s session
+	a.So(record.Decode(&s), should.BeNil)
+	// The first column that matches case-insensitively is used.
+	a.So(s, should.Resemble, session{DevEUI: "0102030405060708", NwkSKey: "01", AppSKey: "02"})
+	// Columns with the exact name take precedence.
+	a.So(record.Get("APPSKEY"), should.Equal, "03")
+}
+
+func TestReaderErrors(t *testing.T) {
+	a := assertions.New(t)
+
+	_, err := csv.Config{}.NewReader(strings.NewReader(""))
+	a.So(err, should.NotBeNil)
+
+	_, err = csv.Config{}.NewReader(strings.NewReader("name\ndev-1\n"), "devEui")
+	a.So(err, should.NotBeNil)
+	a.So(err.Error(), should.ContainSubstring, "devEui")
+
+	_, err = csv.Config{}.NewReader(strings.NewReader("name,name\n"))
+	a.So(err, should.NotBeNil)
+
+	r, err := csv.Config{}.NewReader(strings.NewReader("devEui,name\n0102030405060708,dev-1\n0102030405060709,\"dev-2\n"))
+	a.So(err, should.BeNil)
+	_, err = r.Read()
+	a.So(err, should.BeNil)
+	_, err = r.Read()
+	a.So(err, should.NotBeNil)
+	a.So(err.Error(), should.ContainSubstring, "line 3")
+
+	for _, cfg := range []csv.Config{
+		{Delimiter: ";;"},
+		{Delimiter: `"`},
+		{Encoding: "unknown"},
+	} {
+		a.So(cfg.Initialize(), should.NotBeNil)
+	}
+}
+
+func TestDelimiterAndEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config csv.Config
+		data   []byte
+	}{
+		{
+			name:   "Semicolon",
+			config: csv.Config{Delimiter: ";"},
+			data:   []byte("devEui;name\n0102030405060708;Café\n"),
+		},
+		{
+			name:   "Tab",
+			config: csv.Config{Delimiter: "tab"},
+			data:   []byte("devEui\tname\n0102030405060708\tCafé\n"),
+		},
+		{
+			name:   "UTF8BOM",
+			config: csv.Config{},
+			data:   []byte("\xef\xbb\xbfdevEui,name\n0102030405060708,Café\n"),
+		},
+		{
+			name:   "Windows1252",
+			config: csv.Config{Encoding: "windows-1252"},
+			data:   []byte("devEui,name\n0102030405060708,Caf\xe9\n"),
+		},
+		{
+			name:   "UTF16LEBOM",
+			config: csv.Config{},
+			data:   []byte("\xff\xfed\x00e\x00v\x00E\x00u\x00i\x00,\x00n\x00a\x00m\x00e\x00\n\x00C\x00a\x00f\x00\xe9\x00,\x00C\x00a\x00f\x00\xe9\x00\n\x00"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			a.So(tc.config.Initialize(), should.BeNil)
+			r, err := tc.config.NewReader(bytes.NewReader(tc.data), "devEui", "name")
+			a.So(err, should.BeNil)
+			record, err := r.Read()
+			a.So(err, should.BeNil)
+			a.So(record.Get("name"), should.Equal, "Café")
+		})
+	}
+}
+
+func TestColumnMapping(t *testing.T) {
+	a := assertions.New(t)
+	path := filepath.Join(t.TempDir(), "columns.csv")
+	err := os.WriteFile(path, []byte("# header, column\nDevice EUI,devEui\n\"Name, full\",name\n"), 0o600)
+	a.So(err, should.BeNil)
+
+	cfg := csv.Config{ColumnMappingPath: path}
+	a.So(cfg.Initialize(), should.BeNil)
+	a.So(cfg.ColumnMapping, should.Resemble, map[string]string{
+		"Device EUI": "devEui",
+		"Name, full": "name",
+	})
+
+	r, err := cfg.NewReader(strings.NewReader("Device EUI,\"Name, full\"\n0102030405060708,dev-1\n"), "devEui")
+	a.So(err, should.BeNil)
+	record, err := r.Read()
+	a.So(err, should.BeNil)
+	a.So(record.Get("devEui"), should.Equal, "0102030405060708")
+	a.So(record.Get("name"), should.Equal, "dev-1")
+
+	err = os.WriteFile(path, []byte("Device EUI\n"), 0o600)
+	a.So(err, should.BeNil)
+	a.So(cfg.Initialize(), should.NotBeNil)
+}
@@ -0,0 +1,120 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+)
+
+// applicationSettingsBundle is a single line of the application settings output.
+// Every entity is marshaled with the TTS JSON marshaler, so that it can be passed to ttn-lw-cli as-is.
+type applicationSettingsBundle struct {
+	Application json.RawMessage   `json:"application"`
+	Link        json.RawMessage   `json:"link,omitempty"`
+	Webhooks    []json.RawMessage `json:"webhooks,omitempty"`
+	PubSubs     []json.RawMessage `json:"pubsubs,omitempty"`
+}
+
+func (cfg Config) ExportAppSettings(s source.ApplicationSettingsSource, appID string) error {
+	settings, err := s.ExportApplicationSettings(appID)
+	if err != nil {
+		return errExportApplicationSettings.WithAttributes("application_id", appID).WithCause(err)
+	}
+	appIDs := settings.Application.Ids
+	appIDs.ApplicationId = sanitizeID.Replace(appIDs.ApplicationId)
+	if id := appIDs.ApplicationId; len(id) > maxIDLength {
+		return errAppIDExceedsMaxLength.WithAttributes("id", id)
+	}
+	for _, wh := range settings.Webhooks {
+		wh.Ids.ApplicationIds = appIDs
+		wh.Ids.WebhookId = sanitizeID.Replace(wh.Ids.WebhookId)
+		if id := wh.Ids.WebhookId; len(id) > maxIDLength {
+			return errIntegrationIDExceedsMaxLength.WithAttributes("id", id)
+		}
+	}
+	for _, ps := range settings.PubSubs {
+		ps.Ids.ApplicationIds = appIDs
+		ps.Ids.PubSubId = sanitizeID.Replace(ps.Ids.PubSubId)
+		if id := ps.Ids.PubSubId; len(id) > maxIDLength {
+			return errIntegrationIDExceedsMaxLength.WithAttributes("id", id)
+		}
+	}
+
+	if err := validateApplicationSettings(settings); err != nil {
+		return errInvalidApplicationSettingsFields.WithAttributes("application_id", appIDs.ApplicationId).WithCause(err)
+	}
+	b, err := marshalApplicationSettings(settings)
+	if err != nil {
+		return errFormatApplicationSettings.WithAttributes("application_id", appIDs.ApplicationId).WithCause(err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(b))
+	return err
+}
+
+func validateApplicationSettings(settings *source.ApplicationSettings) error {
+	if err := settings.Application.ValidateFields(); err != nil {
+		return err
+	}
+	if settings.Link != nil {
+		if err := settings.Link.ValidateFields(); err != nil {
+			return err
+		}
+	}
+	for _, wh := range settings.Webhooks {
+		if err := wh.ValidateFields(); err != nil {
+			return err
+		}
+	}
+	for _, ps := range settings.PubSubs {
+		if err := ps.ValidateFields(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshalApplicationSettings(settings *source.ApplicationSettings) ([]byte, error) {
+	var (
+		bundle applicationSettingsBundle
+		err    error
+	)
+	if bundle.Application, err = toJSON(settings.Application); err != nil {
+		return nil, err
+	}
+	if settings.Link != nil {
+		if bundle.Link, err = toJSON(settings.Link); err != nil {
+			return nil, err
+		}
+	}
+	for _, wh := range settings.Webhooks {
+		b, err := toJSON(wh)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Webhooks = append(bundle.Webhooks, b)
+	}
+	for _, ps := range settings.PubSubs {
+		b, err := toJSON(ps)
+		if err != nil {
+			return nil, err
+		}
+		bundle.PubSubs = append(bundle.PubSubs, b)
+	}
+	return json.Marshal(bundle)
+}
@@ -23,4 +23,15 @@ var (
 	errDevIDExceedsMaxLength = errors.Define("dev_id_exceeds_max_length", "device ID `{id}` exceeds max length")
 	errAppIDExceedsMaxLength = errors.Define("app_id_exceeds_max_length", "application ID `{id}` exceeds max length")
 	errNoExportedIDorEUI     = errors.Define("no_exported_id_or_eui", "device `{device_id}` has no exported ID or EUI")
+
+	errExportGateway            = errors.Define("export_gateway", "export gateway `{gateway_id}`")
+	errFormatGateway            = errors.DefineCorruption("format_gateway", "format gateway `{gateway_id}`")
+	errInvalidGatewayFields     = errors.DefineInvalidArgument("invalid_gateway_fields", "invalid fields for gateway `{gateway_id}`")
+	errGtwIDExceedsMaxLength    = errors.Define("gtw_id_exceeds_max_length", "gateway ID `{id}` exceeds max length")
+	errNoExportedGatewayIDorEUI = errors.Define("no_exported_gateway_id_or_eui", "gateway `{gateway_id}` has no exported ID or EUI")
+
+	errExportApplicationSettings        = errors.Define("export_application_settings", "export application settings of `{application_id}`")
+	errFormatApplicationSettings        = errors.DefineCorruption("format_application_settings", "format application settings of `{application_id}`")
+	errInvalidApplicationSettingsFields = errors.DefineInvalidArgument("invalid_application_settings_fields", "invalid fields for application settings of `{application_id}`")
+	errIntegrationIDExceedsMaxLength    = errors.Define("integration_id_exceeds_max_length", "integration ID `{id}` exceeds max length")
 )
@@ -31,8 +31,8 @@ const (
 
 var sanitizeID = strings.NewReplacer("_", "-")
 
-func toJSON(dev *ttnpb.EndDevice) ([]byte, error) {
-	return jsonpb.TTN().Marshal(dev)
+func toJSON(v any) ([]byte, error) {
+	return jsonpb.TTN().Marshal(v)
 }
 
 type Config struct {
@@ -87,6 +87,47 @@ func (cfg Config) ExportDev(s source.Source, devID string) error {
 			"dev_eui", dev.Ids.DevEui,
 		).WithCause(err)
 	}
+	if _, err := fmt.Fprintln(os.Stdout, string(b)); err != nil {
+		return err
+	}
+	if h, ok := s.(source.ExportedDeviceHandler); ok {
+		return h.DeviceExported(dev)
+	}
+	return nil
+}
+
+func (cfg Config) ExportGtw(s source.GatewaySource, gtwID string) error {
+	gtw, err := s.ExportGateway(gtwID)
+	if err != nil {
+		return errExportGateway.WithAttributes("gateway_id", gtwID).WithCause(err)
+	}
+	eui := gtw.Ids.Eui
+
+	if gtw.Ids.GatewayId == "" {
+		if eui == nil {
+			return errNoExportedGatewayIDorEUI.WithAttributes("gateway_id", gtwID)
+		}
+		gtw.Ids.GatewayId = "eui-" + strings.ToLower(hex.EncodeToString(eui))
+	}
+
+	gtw.Ids.GatewayId = sanitizeID.Replace(gtw.Ids.GatewayId)
+	if id := gtw.Ids.GatewayId; len(id) > maxIDLength {
+		return errGtwIDExceedsMaxLength.WithAttributes("id", id)
+	}
+
+	if err := gtw.ValidateFields(); err != nil {
+		return errInvalidGatewayFields.WithAttributes(
+			"gateway_id", gtw.Ids.GatewayId,
+			"eui", gtw.Ids.Eui,
+		).WithCause(err)
+	}
+	b, err := toJSON(gtw)
+	if err != nil {
+		return errFormatGateway.WithAttributes(
+			"gateway_id", gtw.Ids.GatewayId,
+			"eui", gtw.Ids.Eui,
+		).WithCause(err)
+	}
 	_, err = fmt.Fprintln(os.Stdout, string(b))
 	return err
 }
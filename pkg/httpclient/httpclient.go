@@ -0,0 +1,92 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpclient provides an HTTP client for the REST APIs of sources, that retries failed requests,
+// limits the request rate and redacts credentials from logs.
+package httpclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+const (
+	// DefaultTimeout is the timeout of a single request attempt.
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxRetries is the maximum number of retries of requests that are rate limited or fail with a server error.
+	DefaultMaxRetries = 5
+	// DefaultBackoff is the backoff before the first retry. The backoff doubles on each retry.
+	DefaultBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the maximum backoff between retries.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultRateLimit is the maximum number of requests per second.
+	DefaultRateLimit = 10
+	// DefaultBurst is the maximum number of requests that are sent at once.
+	DefaultBurst = 10
+)
+
+// Config is the configuration of the HTTP client.
+type Config struct {
+	// Timeout is the timeout of a single request attempt.
+	Timeout time.Duration
+	// MaxRetries is the maximum number of retries of a request.
+	MaxRetries uint
+	// Backoff is the backoff before the first retry.
+	Backoff time.Duration
+	// MaxBackoff is the maximum backoff between retries.
+	MaxBackoff time.Duration
+	// RateLimit is the maximum number of requests per second. Zero disables rate limiting.
+	RateLimit float64
+	// Burst is the maximum number of requests that are sent at once.
+	Burst uint
+}
+
+// Flags returns the flags of the HTTP client configuration.
+// The defaults are read from the environment variables with envPrefix.
+func (c *Config) Flags(envPrefix string) *pflag.FlagSet {
+	flags := &pflag.FlagSet{}
+	flags.DurationVar(&c.Timeout,
+		"http-timeout",
+		util.EnvDuration(envPrefix+"HTTP_TIMEOUT", DefaultTimeout),
+		"Timeout of a single HTTP request")
+	flags.UintVar(&c.MaxRetries,
+		"http-max-retries",
+		util.EnvUint(envPrefix+"HTTP_MAX_RETRIES", DefaultMaxRetries),
+		"Maximum number of retries of HTTP requests that are rate limited or fail with a server error")
+	flags.DurationVar(&c.Backoff,
+		"http-backoff",
+		util.EnvDuration(envPrefix+"HTTP_BACKOFF", DefaultBackoff),
+		"Backoff before retrying an HTTP request. The backoff doubles on each retry")
+	flags.Float64Var(&c.RateLimit,
+		"http-rate-limit",
+		util.EnvFloat(envPrefix+"HTTP_RATE_LIMIT", DefaultRateLimit),
+		"Maximum number of HTTP requests per second. Set to 0 to disable rate limiting")
+	return flags
+}
+
+// New returns an HTTP client that uses the transport of base. Retries are logged to logger.
+// If base is nil, http.DefaultTransport is used.
+func (c Config) New(base http.RoundTripper, logger *zap.SugaredLogger) *http.Client {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport: NewTransport(base, c, logger),
+	}
+}
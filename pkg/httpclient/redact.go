@@ -0,0 +1,74 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces credentials in logs.
+const redacted = "xxxxx"
+
+// credentialParams are the query parameters that contain credentials.
+var credentialParams = map[string]bool{
+	"access_token": true,
+	"api_key":      true,
+	"apikey":       true,
+	"auth":         true,
+	"key":          true,
+	"password":     true,
+	"secret":       true,
+	"token":        true,
+}
+
+// RedactURL returns the URL with credentials in the user info and query parameters redacted.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	ret := *u
+	if ret.User != nil {
+		ret.User = url.User(redacted)
+	}
+	if ret.RawQuery != "" {
+		query := ret.Query()
+		for param := range query {
+			if credentialParams[strings.ToLower(param)] {
+				query.Set(param, redacted)
+			}
+		}
+		ret.RawQuery = query.Encode()
+	}
+	return ret.String()
+}
+
+// RedactError redacts the URL of errors returned by http.Client.
+func RedactError(err error) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	u, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return err
+	}
+	return &url.Error{
+		Op:  urlErr.Op,
+		URL: RedactURL(u),
+		Err: urlErr.Err,
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// tokenBucket limits the rate of requests.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst uint) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available, or the context is done.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// transport is an http.RoundTripper that limits the request rate, and retries requests that are rate
+// limited or fail with a server error with exponential backoff.
+type transport struct {
+	base    http.RoundTripper
+	config  Config
+	limiter *tokenBucket
+	logger  *zap.SugaredLogger
+}
+
+// NewTransport returns an http.RoundTripper that wraps base with the retries, rate limit and timeouts of
+// the configuration. Requests with a body are only retried if the body can be read again.
+func NewTransport(base http.RoundTripper, config Config, logger *zap.SugaredLogger) http.RoundTripper {
+	if logger == nil {
+		logger = zap.NewNop().Sugar()
+	}
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = DefaultMaxBackoff
+	}
+	if config.Burst == 0 {
+		config.Burst = DefaultBurst
+	}
+	t := &transport{
+		base:   base,
+		config: config,
+		logger: logger,
+	}
+	if config.RateLimit > 0 {
+		t.limiter = newTokenBucket(config.RateLimit, config.Burst)
+	}
+	return t
+}
+
+// isIdempotent returns true if the request can be sent again without side effects.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry returns true if the request should be retried. Rate limited requests are always retried,
+// since the server did not process them. Server and network errors are only retried for idempotent requests.
+func shouldRetry(req *http.Request, res *http.Response, err error) bool {
+	switch {
+	case req.Context().Err() != nil:
+		return false
+	case err != nil:
+		return isIdempotent(req)
+	case res.StatusCode == http.StatusTooManyRequests:
+		return true
+	case res.StatusCode >= 500:
+		return isIdempotent(req)
+	default:
+		return false
+	}
+}
+
+// backoff returns the backoff before the next attempt. The Retry-After header of the response is used if set.
+func (t *transport) backoff(attempt uint, res *http.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, t.config.MaxBackoff)
+		}
+	}
+	backoff := t.config.Backoff
+	for i := uint(0); i < attempt && backoff < t.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, t.config.MaxBackoff)
+}
+
+// cancelBody cancels the context of a request attempt when the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// roundTrip sends a single attempt of the request.
+func (t *transport) roundTrip(req *http.Request, attempt uint) (*http.Response, context.CancelFunc, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.config.Timeout)
+	}
+	attemptReq := req.Clone(ctx)
+	if attempt > 0 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+	res, err := t.base.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return res, cancel, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	canRetry := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for attempt := uint(0); ; attempt++ {
+		if t.limiter != nil {
+			if err := t.limiter.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		res, cancel, err := t.roundTrip(req, attempt)
+		if !canRetry || attempt >= t.config.MaxRetries || !shouldRetry(req, res, err) {
+			if err != nil {
+				return nil, err
+			}
+			res.Body = cancelBody{ReadCloser: res.Body, cancel: cancel}
+			return res, nil
+		}
+
+		backoff := t.backoff(attempt, res)
+		logger := t.logger.With(
+			"method", req.Method,
+			"url", RedactURL(req.URL),
+			"attempt", attempt+1,
+			"backoff", backoff,
+		)
+		if err != nil {
+			logger.Debugw("Request failed, will retry", "error", RedactError(err))
+		} else {
+			logger.Debugw("Request failed, will retry", "code", res.StatusCode)
+			io.Copy(io.Discard, res.Body) //nolint:errcheck
+			res.Body.Close()
+			cancel()
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
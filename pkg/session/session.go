@@ -0,0 +1,101 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package session creates the sessions and MAC states of exported devices.
+package session
+
+import (
+	"github.com/TheThingsNetwork/go-utils/random"
+	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
+	"go.thethings.network/lorawan-stack/v3/pkg/networkserver/mac"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+// Keys are the hex encoded DevAddr and session keys of a device.
+type Keys struct {
+	DevAddr string
+	AppSKey string
+	// FNwkSIntKey is the NwkSKey for LoRaWAN 1.0.x devices.
+	FNwkSIntKey string
+	// SNwkSIntKey is only used by LoRaWAN 1.1 devices. It defaults to FNwkSIntKey.
+	SNwkSIntKey string
+	// NwkSEncKey is only used by LoRaWAN 1.1 devices. It defaults to FNwkSIntKey.
+	NwkSEncKey string
+}
+
+// New returns the session of the device with the keys. Sessions of devices that support join get a
+// new session key ID.
+func New(dev *ttnpb.EndDevice, keys Keys) (*ttnpb.Session, error) {
+	var err error
+	session := &ttnpb.Session{
+		Keys: &ttnpb.SessionKeys{
+			AppSKey:     &ttnpb.KeyEnvelope{},
+			FNwkSIntKey: &ttnpb.KeyEnvelope{},
+		},
+	}
+	session.DevAddr, err = util.UnmarshalTextToBytes(&types.DevAddr{}, keys.DevAddr)
+	if err != nil {
+		return nil, err
+	}
+	if dev.SupportsJoin {
+		session.StartedAt = timestamppb.Now()
+		session.Keys.SessionKeyId = random.Bytes(16)
+	}
+	session.Keys.AppSKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, keys.AppSKey)
+	if err != nil {
+		return nil, err
+	}
+	session.Keys.FNwkSIntKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, keys.FNwkSIntKey)
+	if err != nil {
+		return nil, err
+	}
+	if dev.LorawanVersion.Compare(ttnpb.MACVersion_MAC_V1_1) >= 0 {
+		if keys.SNwkSIntKey == "" {
+			keys.SNwkSIntKey = keys.FNwkSIntKey
+		}
+		if keys.NwkSEncKey == "" {
+			keys.NwkSEncKey = keys.FNwkSIntKey
+		}
+		session.Keys.SNwkSIntKey = &ttnpb.KeyEnvelope{}
+		session.Keys.SNwkSIntKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, keys.SNwkSIntKey)
+		if err != nil {
+			return nil, err
+		}
+		session.Keys.NwkSEncKey = &ttnpb.KeyEnvelope{}
+		session.Keys.NwkSEncKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, keys.NwkSEncKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// NewMACState returns the MAC state of a device with a session. The current parameters are the desired
+// parameters, with the Rx1 delay of the MAC settings of the device, or 1 second if it is not set.
+func NewMACState(dev *ttnpb.EndDevice, fpStore *frequencyplans.Store) (*ttnpb.MACState, error) {
+	macState, err := mac.NewState(dev, fpStore, &ttnpb.MACSettings{})
+	if err != nil {
+		return nil, err
+	}
+	macState.CurrentParameters = macState.DesiredParameters
+	macState.CurrentParameters.Rx1Delay = ttnpb.RxDelay_RX_DELAY_1
+	if rx1Delay := dev.GetMacSettings().GetRx1Delay(); rx1Delay != nil {
+		macState.CurrentParameters.Rx1Delay = rx1Delay.Value
+	}
+	return macState, nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+var errInvalidMACVersion = errors.DefineInvalidArgument("invalid_mac_version", "invalid MAC version `{mac_version}`")
+
+// versions are the MAC and PHY versions of LoRaWAN versions. Versions without a Regional Parameters
+// revision use the first revision.
+var versions = map[string]struct {
+	mac ttnpb.MACVersion
+	phy ttnpb.PHYVersion
+}{
+	"1.0":    {ttnpb.MACVersion_MAC_V1_0, ttnpb.PHYVersion_TS001_V1_0},
+	"1.0.0":  {ttnpb.MACVersion_MAC_V1_0, ttnpb.PHYVersion_TS001_V1_0},
+	"1.0.1":  {ttnpb.MACVersion_MAC_V1_0_1, ttnpb.PHYVersion_TS001_V1_0_1},
+	"1.0.2":  {ttnpb.MACVersion_MAC_V1_0_2, ttnpb.PHYVersion_RP001_V1_0_2},
+	"1.0.2a": {ttnpb.MACVersion_MAC_V1_0_2, ttnpb.PHYVersion_RP001_V1_0_2},
+	"1.0.2b": {ttnpb.MACVersion_MAC_V1_0_2, ttnpb.PHYVersion_RP001_V1_0_2_REV_B},
+	"1.0.3":  {ttnpb.MACVersion_MAC_V1_0_3, ttnpb.PHYVersion_RP001_V1_0_3_REV_A},
+	"1.0.4":  {ttnpb.MACVersion_MAC_V1_0_4, ttnpb.PHYVersion_RP002_V1_0_4},
+	"1.1":    {ttnpb.MACVersion_MAC_V1_1, ttnpb.PHYVersion_RP001_V1_1_REV_A},
+	"1.1.0":  {ttnpb.MACVersion_MAC_V1_1, ttnpb.PHYVersion_RP001_V1_1_REV_A},
+	"1.1.0a": {ttnpb.MACVersion_MAC_V1_1, ttnpb.PHYVersion_RP001_V1_1_REV_A},
+	"1.1.0b": {ttnpb.MACVersion_MAC_V1_1, ttnpb.PHYVersion_RP001_V1_1_REV_B},
+}
+
+// ParseMACVersion returns the MAC and PHY version of a LoRaWAN version, for example `1.0.3`, `v1.0.2b`
+// or `MAC_V1_0_3`.
+func ParseMACVersion(v string) (ttnpb.MACVersion, ttnpb.PHYVersion, error) {
+	s := strings.ToLower(strings.TrimSpace(v))
+	if name, ok := strings.CutPrefix(s, "mac_v"); ok {
+		s = strings.ReplaceAll(name, "_", ".")
+	}
+	version, ok := versions[strings.TrimPrefix(s, "v")]
+	if !ok {
+		return ttnpb.MACVersion_MAC_UNKNOWN, ttnpb.PHYVersion_PHY_UNKNOWN,
+			errInvalidMACVersion.WithAttributes("mac_version", v)
+	}
+	return version.mac, version.phy, nil
+}
@@ -0,0 +1,52 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session_test
+
+import (
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
+)
+
+func TestParseMACVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		mac     ttnpb.MACVersion
+		phy     ttnpb.PHYVersion
+	}{
+		{"1.0", ttnpb.MACVersion_MAC_V1_0, ttnpb.PHYVersion_TS001_V1_0},
+		{"1.0.2", ttnpb.MACVersion_MAC_V1_0_2, ttnpb.PHYVersion_RP001_V1_0_2},
+		{"1.0.2B", ttnpb.MACVersion_MAC_V1_0_2, ttnpb.PHYVersion_RP001_V1_0_2_REV_B},
+		{"v1.0.3", ttnpb.MACVersion_MAC_V1_0_3, ttnpb.PHYVersion_RP001_V1_0_3_REV_A},
+		{"1.0.4", ttnpb.MACVersion_MAC_V1_0_4, ttnpb.PHYVersion_RP002_V1_0_4},
+		{"MAC_V1_0_4", ttnpb.MACVersion_MAC_V1_0_4, ttnpb.PHYVersion_RP002_V1_0_4},
+		{"1.1.0b", ttnpb.MACVersion_MAC_V1_1, ttnpb.PHYVersion_RP001_V1_1_REV_B},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			a := assertions.New(t)
+			mac, phy, err := session.ParseMACVersion(tc.version)
+			a.So(err, should.BeNil)
+			a.So(mac, should.Equal, tc.mac)
+			a.So(phy, should.Equal, tc.phy)
+		})
+	}
+
+	_, _, err := session.ParseMACVersion("1.2")
+	assertions.New(t).So(err, should.NotBeNil)
+}
@@ -19,10 +19,14 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/iotwireless"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/spf13/pflag"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
 	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
 )
@@ -35,6 +39,23 @@ type Config struct {
 	AppID           string
 	FrequencyPlanID string
 
+	DestinationName  string
+	DeviceProfileID  string
+	ServiceProfileID string
+	Tags             map[string]string
+
+	// AppIDs maps destinations, device profiles, service profiles and tags to application IDs.
+	AppIDs map[string]string
+
+	DisassociateSourceDevice bool
+	DeleteSourceDevice       bool
+
+	appIDMappingPath string
+	region           string
+	profile          string
+	roleARN          string
+	endpointURL      string
+
 	flags   *pflag.FlagSet
 	fpStore *frequencyplans.Store
 }
@@ -52,7 +73,56 @@ func New() *Config {
 	c.flags.StringVar(&c.FrequencyPlanID,
 		"frequency-plan-id",
 		os.Getenv("FREQUENCY_PLAN_ID"),
-		"Frequency Plan ID for the exported devices")
+		"Frequency Plan ID for the exported devices and gateways")
+	c.flags.StringVar(&c.region,
+		"region",
+		os.Getenv("AWS_REGION"),
+		"(optional) AWS region. Defaults to the region of the shared AWS configuration")
+	c.flags.StringVar(&c.profile,
+		"profile",
+		os.Getenv("AWS_PROFILE"),
+		"(optional) Profile of the shared AWS configuration")
+	c.flags.StringVar(&c.roleARN,
+		"role-arn",
+		os.Getenv("AWS_ROLE_ARN"),
+		"(optional) ARN of an IAM role to assume")
+	c.flags.StringVar(&c.endpointURL,
+		"endpoint-url",
+		os.Getenv("AWS_ENDPOINT_URL"),
+		"(optional) Endpoint URL of the AWS IoT Wireless API. Only for testing")
+	c.flags.BoolVar(&c.DisassociateSourceDevice,
+		"disassociate-thing",
+		os.Getenv("AWS_DISASSOCIATE_THING") == "true",
+		`Disassociate the exported devices from their AWS IoT thing.
+The devices are not removed from the AWS IoT network server, and can still communicate with AWS IoT.
+Use --delete-source-device to stop the devices from communicating with AWS IoT`)
+	c.flags.BoolVar(&c.DeleteSourceDevice,
+		"delete-source-device",
+		os.Getenv("DELETE_SOURCE_DEVICE") == "true",
+		`Delete the exported devices from AWS IoT, after they are exported.
+This stops the devices from communicating with AWS IoT, and can not be undone`)
+	c.flags.StringVar(&c.DestinationName,
+		"destination-name",
+		os.Getenv("AWS_DESTINATION_NAME"),
+		"(optional) Only export devices with this destination name")
+	c.flags.StringVar(&c.DeviceProfileID,
+		"device-profile-id",
+		os.Getenv("AWS_DEVICE_PROFILE_ID"),
+		"(optional) Only export devices with this device profile ID")
+	c.flags.StringVar(&c.ServiceProfileID,
+		"service-profile-id",
+		os.Getenv("AWS_SERVICE_PROFILE_ID"),
+		"(optional) Only export devices with this service profile ID")
+	c.flags.StringToStringVar(&c.Tags,
+		"tag",
+		nil,
+		"(optional) Only export devices with these tags (key=value). Can be repeated")
+	c.flags.StringVar(&c.appIDMappingPath,
+		"app-id-mapping-file",
+		os.Getenv("AWS_APP_ID_MAPPING_FILE"),
+		`(optional) Path to a file that maps devices to application IDs, one mapping per line.
+Devices are mapped by tag:<key>=<value>, destination:<name>, device-profile:<id> or service-profile:<id>, in this order.
+Devices that are not mapped use the application ID set with --app-id`)
 
 	return c
 }
@@ -61,18 +131,45 @@ func New() *Config {
 func (c *Config) Initialize(rootCfg source.Config) error {
 	c.Config = rootCfg
 
-	if c.AppID == "" {
+	if c.appIDMappingPath != "" {
+		var err error
+		if c.AppIDs, err = util.ReadMappingFile(c.appIDMappingPath); err != nil {
+			return err
+		}
+	}
+	if c.AppID == "" && len(c.AppIDs) == 0 && c.Entity == source.EntityEndDevices {
 		return errNoAppID.New()
 	}
 	if c.FrequencyPlanID == "" {
 		return errNoFrequencyPlanID.New()
 	}
 
-	cfg, err := config.LoadDefaultConfig(context.Background())
+	// Source devices are not changed during a dry run.
+	if c.DryRun && (c.DisassociateSourceDevice || c.DeleteSourceDevice) {
+		c.Logger.Warn("Cannot disassociate or delete source devices during a dry run.")
+		c.DisassociateSourceDevice, c.DeleteSourceDevice = false, false
+	}
+
+	var opts []func(*config.LoadOptions) error
+	if c.region != "" {
+		opts = append(opts, config.WithRegion(c.region))
+	}
+	if c.profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(c.profile))
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), opts...)
 	if err != nil {
 		return err
 	}
-	c.Client = iotwireless.NewFromConfig(cfg)
+	if c.roleARN != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), c.roleARN)
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	c.Client = iotwireless.NewFromConfig(cfg, func(o *iotwireless.Options) {
+		if c.endpointURL != "" {
+			o.BaseEndpoint = aws.String(c.endpointURL)
+		}
+	})
 
 	fpFetcher, err := fetch.FromHTTP(http.DefaultClient, c.FrequencyPlansURL)
 	if err != nil {
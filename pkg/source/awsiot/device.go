@@ -20,12 +20,13 @@ import (
 	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
 	ttntypes "go.thethings.network/lorawan-stack/v3/pkg/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Device struct{ *types.LoRaWANDevice }
 
 type DeviceIdentifiers struct {
-	Id, Name, Description *string
+	Id, Arn, Name, Description, DestinationName, ThingArn *string
 }
 
 // SetOTAADevice sets the OTAA device fields.
@@ -109,6 +110,13 @@ func (d Device) SetABPDevice(dev *ttnpb.EndDevice) (err error) {
 			return errInvalidDevAddr.WithAttributes("dev_addr", aws.ToString(devAddr)).WithCause(err)
 		}
 	}
+
+	// AWS IoT does not expose the current frame counters, only the initial uplink frame counter.
+	// This is the first frame counter that the device sends, so the last frame counter is one less.
+	if fCntStart := d.fCntStart(); fCntStart > 0 {
+		dev.Session.LastFCntUp = uint32(fCntStart) - 1
+	}
+	dev.Session.StartedAt = timestamppb.Now()
 	return nil
 }
 
@@ -145,6 +153,16 @@ func (d Device) devAddr() (addr *string) {
 	return addr
 }
 
+func (d Device) fCntStart() (fCnt int32) {
+	if d.AbpV1_0_x != nil {
+		fCnt = aws.ToInt32(d.AbpV1_0_x.FCntStart)
+	}
+	if d.AbpV1_1 != nil {
+		fCnt = aws.ToInt32(d.AbpV1_1.FCntStart)
+	}
+	return fCnt
+}
+
 func (d Device) rootKeys() (keys rootKeys) {
 	if d.OtaaV1_0_x != nil {
 		keys.appKey = d.OtaaV1_0_x.AppKey
@@ -0,0 +1,77 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsiot_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless/types"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/awsiot"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+const testKey = "01020304050607080102030405060708"
+
+func TestSetABPDevice(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		version    ttnpb.MACVersion
+		fCntStart  *int32
+		lastFCntUp uint32
+	}{
+		{name: "V1_0_NoFCntStart", version: ttnpb.MACVersion_MAC_V1_0_3},
+		{name: "V1_0_FCntStart0", version: ttnpb.MACVersion_MAC_V1_0_3, fCntStart: aws.Int32(0)},
+		{name: "V1_0_FCntStart1", version: ttnpb.MACVersion_MAC_V1_0_3, fCntStart: aws.Int32(1)},
+		{name: "V1_0_FCntStart100", version: ttnpb.MACVersion_MAC_V1_0_3, fCntStart: aws.Int32(100), lastFCntUp: 99},
+		{name: "V1_1_FCntStart100", version: ttnpb.MACVersion_MAC_V1_1, fCntStart: aws.Int32(100), lastFCntUp: 99},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			awsDev := &types.LoRaWANDevice{}
+			if tc.version == ttnpb.MACVersion_MAC_V1_1 {
+				awsDev.AbpV1_1 = &types.AbpV11{
+					DevAddr:   aws.String("01020304"),
+					FCntStart: tc.fCntStart,
+					SessionKeys: &types.SessionKeysAbpV11{
+						AppSKey:     aws.String(testKey),
+						FNwkSIntKey: aws.String(testKey),
+						NwkSEncKey:  aws.String(testKey),
+						SNwkSIntKey: aws.String(testKey),
+					},
+				}
+			} else {
+				awsDev.AbpV1_0_x = &types.AbpV10x{
+					DevAddr:   aws.String("01020304"),
+					FCntStart: tc.fCntStart,
+					SessionKeys: &types.SessionKeysAbpV10x{
+						AppSKey: aws.String(testKey),
+						NwkSKey: aws.String(testKey),
+					},
+				}
+			}
+			dev := &ttnpb.EndDevice{LorawanVersion: tc.version}
+			err := awsiot.Device{LoRaWANDevice: awsDev}.SetABPDevice(dev)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			a.So(dev.Session.LastFCntUp, should.Equal, tc.lastFCntUp)
+			a.So(dev.Session.DevAddr, should.Resemble, []byte{0x01, 0x02, 0x03, 0x04})
+		})
+	}
+}
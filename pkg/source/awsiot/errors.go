@@ -21,7 +21,16 @@ var (
 	errInvalidPHYForMACVersion = errors.DefineInvalidArgument("invalid_phy_for_mac_version", "invalid PHY version `{phy_version}` for MAC version `{mac_version}`")
 	errInvalidDevAddr          = errors.DefineInvalidArgument("invalid_dev_addr", "invalid DevAddr `{dev_addr}`")
 	errInvalidDevEUI           = errors.DefineInvalidArgument("invalid_dev_eui", "invalid DevEUI `{dev_eui}`")
+	errInvalidGatewayEUI       = errors.DefineInvalidArgument("invalid_gateway_eui", "invalid gateway EUI `{gateway_eui}`")
 	errInvalidJoinEUI          = errors.DefineInvalidArgument("invalid_join_eui", "invalid JoinEUI `{join_eui}`")
 	errInvalidKey              = errors.DefineInvalidArgument("invalid_key", "invalid key `{key}`")
 	errEmptyKey                = errors.DefineInvalidArgument("empty_key", "empty key `{key}`")
+	errInvalidPosition         = errors.DefineInvalidArgument("invalid_position", "invalid position")
+	errNoAppID                 = errors.DefineInvalidArgument("no_app_id", "no application ID for device `{device_id}`")
+	errUnmappedApplication     = errors.DefineInvalidArgument(
+		"unmapped_application",
+		"application `{app_id}` is not the configured application ID, and not a destination, profile or tag key",
+	)
+	errInvalidFilter  = errors.DefineInvalidArgument("invalid_filter", "invalid filter `{key}`")
+	errFilterConflict = errors.DefineInvalidArgument("filter_conflict", "filter `{key}` conflicts with configured `{value}`")
 )
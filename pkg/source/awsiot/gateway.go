@@ -0,0 +1,84 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsiot
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless"
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless/types"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	ttntypes "go.thethings.network/lorawan-stack/v3/pkg/types"
+)
+
+// ExportGateway implements the source.GatewaySource interface.
+func (s Source) ExportGateway(gtwID string) (*ttnpb.Gateway, error) {
+	resp, err := s.config.Client.GetWirelessGateway(s.ctx, &iotwireless.GetWirelessGatewayInput{
+		IdentifierType: types.WirelessGatewayIdTypeWirelessGatewayId,
+		Identifier:     aws.String(gtwID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gtw := &ttnpb.Gateway{
+		Ids:              &ttnpb.GatewayIdentifiers{},
+		Name:             aws.ToString(resp.Name),
+		Description:      aws.ToString(resp.Description),
+		Attributes:       map[string]string{"aws-wireless-gateway-id": aws.ToString(resp.Id)},
+		FrequencyPlanId:  s.config.FrequencyPlanID,
+		FrequencyPlanIds: []string{s.config.FrequencyPlanID},
+		EnforceDutyCycle: true,
+	}
+	if resp.LoRaWAN == nil || resp.LoRaWAN.GatewayEui == nil {
+		return nil, errInvalidGatewayEUI.WithAttributes("gateway_eui", "")
+	}
+	gtwEUI := aws.ToString(resp.LoRaWAN.GatewayEui)
+	gtw.Ids.Eui, err = util.UnmarshalTextToBytes(&ttntypes.EUI64{}, gtwEUI)
+	if err != nil {
+		return nil, errInvalidGatewayEUI.WithAttributes("gateway_eui", gtwEUI).WithCause(err)
+	}
+	gtw.Ids.GatewayId = "eui-" + strings.ToLower(gtwEUI)
+	if rfRegion := aws.ToString(resp.LoRaWAN.RfRegion); rfRegion != "" {
+		gtw.Attributes["aws-rf-region"] = rfRegion
+	}
+	return gtw, nil
+}
+
+// RangeGateways implements the source.GatewaySource interface.
+func (s Source) RangeGateways(f func(source.GatewaySource, string) error) error {
+	var nextToken *string
+	for {
+		resp, err := s.config.Client.ListWirelessGateways(s.ctx,
+			&iotwireless.ListWirelessGatewaysInput{
+				NextToken:  nextToken,
+				MaxResults: 100,
+			})
+		if err != nil {
+			return err
+		}
+		for _, g := range resp.WirelessGatewayList {
+			if err := f(s, aws.ToString(g.Id)); err != nil {
+				return err
+			}
+		}
+		if nextToken = resp.NextToken; nextToken == nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsiot
+
+import (
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless"
+)
+
+// Prefixes of the keys in the application ID mapping file.
+const (
+	tagMappingPrefix            = "tag:"
+	destinationMappingPrefix    = "destination:"
+	deviceProfileMappingPrefix  = "device-profile:"
+	serviceProfileMappingPrefix = "service-profile:"
+)
+
+// applicationIDs returns the application IDs of the mapping file and the default application ID.
+func (s Source) applicationIDs() []string {
+	seen := make(map[string]bool)
+	if s.config.AppID != "" {
+		seen[s.config.AppID] = true
+	}
+	for _, appID := range s.config.AppIDs {
+		seen[appID] = true
+	}
+	appIDs := make([]string, 0, len(seen))
+	for appID := range seen {
+		appIDs = append(appIDs, appID)
+	}
+	sort.Strings(appIDs)
+	return appIDs
+}
+
+// hasTagMapping returns true if devices are mapped by tags.
+func (s Source) hasTagMapping() bool {
+	for key := range s.config.AppIDs {
+		if strings.HasPrefix(key, tagMappingPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// applicationID returns the application ID of the device. Devices are mapped by tags, destination,
+// device profile and service profile, in this order. Devices that are not mapped use the configured
+// application ID. An empty string is returned if the device is not mapped and no application ID is configured.
+func (s Source) applicationID(devIds *DeviceIdentifiers, dev Device) (string, error) {
+	if s.hasTagMapping() {
+		tags, err := s.getTags(devIds.Arn)
+		if err != nil {
+			return "", err
+		}
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if appID, ok := s.config.AppIDs[tagMappingPrefix+key+"="+tags[key]]; ok {
+				return appID, nil
+			}
+		}
+	}
+	for _, key := range []string{
+		destinationMappingPrefix + aws.ToString(devIds.DestinationName),
+		deviceProfileMappingPrefix + aws.ToString(dev.DeviceProfileId),
+		serviceProfileMappingPrefix + aws.ToString(dev.ServiceProfileId),
+	} {
+		if appID, ok := s.config.AppIDs[key]; ok {
+			return appID, nil
+		}
+	}
+	return s.config.AppID, nil
+}
+
+// deviceFilter filters the devices that are exported.
+type deviceFilter struct {
+	destinationName  string
+	deviceProfileID  string
+	serviceProfileID string
+	tags             map[string]string
+}
+
+// withKey returns the filter with a key of the application ID mapping file. Filters that are already
+// set can not be changed.
+func (f deviceFilter) withKey(key string) (deviceFilter, error) {
+	set := func(field *string, value string) error {
+		if *field != "" && *field != value {
+			return errFilterConflict.WithAttributes("key", key, "value", *field)
+		}
+		*field = value
+		return nil
+	}
+	var err error
+	switch {
+	case strings.HasPrefix(key, tagMappingPrefix):
+		tagKey, tagValue, ok := strings.Cut(strings.TrimPrefix(key, tagMappingPrefix), "=")
+		if !ok {
+			return f, errInvalidFilter.WithAttributes("key", key)
+		}
+		if value, ok := f.tags[tagKey]; ok && value != tagValue {
+			return f, errFilterConflict.WithAttributes("key", key, "value", value)
+		}
+		tags := maps.Clone(f.tags)
+		if tags == nil {
+			tags = make(map[string]string)
+		}
+		tags[tagKey] = tagValue
+		f.tags = tags
+	case strings.HasPrefix(key, destinationMappingPrefix):
+		err = set(&f.destinationName, strings.TrimPrefix(key, destinationMappingPrefix))
+	case strings.HasPrefix(key, deviceProfileMappingPrefix):
+		err = set(&f.deviceProfileID, strings.TrimPrefix(key, deviceProfileMappingPrefix))
+	case strings.HasPrefix(key, serviceProfileMappingPrefix):
+		err = set(&f.serviceProfileID, strings.TrimPrefix(key, serviceProfileMappingPrefix))
+	default:
+		return f, errUnmappedApplication.WithAttributes("app_id", key)
+	}
+	return f, err
+}
+
+// matchesTags returns true if the device has all tags.
+func (s Source) matchesTags(arn *string, filter map[string]string) (bool, error) {
+	if len(filter) == 0 {
+		return true, nil
+	}
+	tags, err := s.getTags(arn)
+	if err != nil {
+		return false, err
+	}
+	for key, value := range filter {
+		if tags[key] != value {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getTags returns the tags of an AWS resource.
+func (s Source) getTags(arn *string) (map[string]string, error) {
+	if tags, ok := s.tags[aws.ToString(arn)]; ok {
+		return tags, nil
+	}
+	resp, err := s.config.Client.ListTagsForResource(s.ctx, &iotwireless.ListTagsForResourceInput{
+		ResourceArn: arn,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(resp.Tags))
+	for _, tag := range resp.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	s.tags[aws.ToString(arn)] = tags
+	return tags, nil
+}
@@ -0,0 +1,70 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsiot
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless"
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless/types"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+// geoJSONPosition is a GeoJSON point, as returned by GetResourcePosition.
+type geoJSONPosition struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+	Properties  struct {
+		HorizontalAccuracy float64 `json:"horizontalAccuracy"`
+	} `json:"properties"`
+}
+
+// getLocation returns the location of a wireless device, or nil if the device has no position.
+func (s Source) getLocation(id *string) (*ttnpb.Location, error) {
+	resp, err := s.config.Client.GetResourcePosition(s.ctx, &iotwireless.GetResourcePositionInput{
+		ResourceIdentifier: id,
+		ResourceType:       types.PositionResourceTypeWirelessDevice,
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(resp.GeoJsonPayload) == 0 {
+		return nil, nil
+	}
+	var position geoJSONPosition
+	if err := json.Unmarshal(resp.GeoJsonPayload, &position); err != nil {
+		return nil, errInvalidPosition.WithCause(err)
+	}
+	if position.Type != "Point" || len(position.Coordinates) < 2 {
+		return nil, errInvalidPosition.New()
+	}
+	// GeoJSON coordinates are longitude, latitude and optional altitude.
+	location := &ttnpb.Location{
+		Longitude: position.Coordinates[0],
+		Latitude:  position.Coordinates[1],
+		Accuracy:  int32(math.Round(position.Properties.HorizontalAccuracy)),
+		Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+	}
+	if len(position.Coordinates) > 2 {
+		location.Altitude = int32(math.Round(position.Coordinates[2]))
+	}
+	return location, nil
+}
@@ -26,6 +26,11 @@ import (
 
 type Profile struct{ *types.LoRaWANDeviceProfile }
 
+// frequency returns the frequency in Hz. AWS IoT frequencies are in units of 100 Hz.
+func frequency(v *int32) uint64 {
+	return uint64(aws.ToInt32(v)) * 100
+}
+
 func (p Profile) macVersion() (mac ttnpb.MACVersion, phy ttnpb.PHYVersion, err error) {
 	regParamsRevision := aws.ToString(p.RegParamsRevision)
 
@@ -101,10 +106,10 @@ func (p Profile) SetFields(dev *ttnpb.EndDevice) (err error) {
 
 	m := dev.MacSettings
 	if v := p.ClassBTimeout; v != nil {
-		m.ClassBTimeout = durationpb.New(time.Duration(aws.ToInt32(v)))
+		m.ClassBTimeout = durationpb.New(time.Duration(aws.ToInt32(v)) * time.Second)
 	}
 	if v := p.ClassCTimeout; v != nil {
-		m.ClassCTimeout = durationpb.New(time.Duration(aws.ToInt32(v)))
+		m.ClassCTimeout = durationpb.New(time.Duration(aws.ToInt32(v)) * time.Second)
 	}
 	if v := p.MaxDutyCycle; v != nil {
 		m.DesiredMaxDutyCycle = &ttnpb.AggregatedDutyCycleValue{Value: ttnpb.AggregatedDutyCycle(aws.ToInt32(v))}
@@ -116,7 +121,7 @@ func (p Profile) SetFields(dev *ttnpb.EndDevice) (err error) {
 		m.PingSlotDataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(aws.ToInt32(v))}
 	}
 	if v := p.PingSlotFreq; v != nil {
-		m.PingSlotFrequency = &ttnpb.ZeroableFrequencyValue{Value: uint64(aws.ToInt32(v))}
+		m.PingSlotFrequency = &ttnpb.ZeroableFrequencyValue{Value: frequency(v)}
 	}
 	if v := p.PingSlotPeriod; v != nil {
 		m.PingSlotPeriodicity = &ttnpb.PingSlotPeriodValue{Value: ttnpb.PingSlotPeriod(aws.ToInt32(v))}
@@ -131,9 +136,59 @@ func (p Profile) SetFields(dev *ttnpb.EndDevice) (err error) {
 		m.Rx1DataRateOffset = &ttnpb.DataRateOffsetValue{Value: ttnpb.DataRateOffset(aws.ToInt32(v))}
 	}
 	if v := p.RxFreq2; v != nil {
-		m.Rx2Frequency = &ttnpb.FrequencyValue{Value: uint64(aws.ToInt32(v))}
+		m.Rx2Frequency = &ttnpb.FrequencyValue{Value: frequency(v)}
 	}
 	m.Supports_32BitFCnt = &ttnpb.BoolValue{Value: p.Supports32BitFCnt}
 
 	return nil
 }
+
+type ServiceProfile struct {
+	*types.LoRaWANGetServiceProfileInfo
+}
+
+// SetFields sets the fields of the device from the service profile. The names of settings
+// that can not be represented on The Things Stack are returned.
+func (p ServiceProfile) SetFields(dev *ttnpb.EndDevice) (unsupported []string) {
+	m := dev.MacSettings
+	if p.DrMin != nil || p.DrMax != nil {
+		dynamic := &ttnpb.ADRSettings_DynamicMode{}
+		if v := p.DrMin; v != nil {
+			dynamic.MinDataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(aws.ToInt32(v))}
+		}
+		if v := p.DrMax; v != nil {
+			dynamic.MaxDataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(aws.ToInt32(v))}
+		}
+		m.Adr = &ttnpb.ADRSettings{Mode: &ttnpb.ADRSettings_Dynamic{Dynamic: dynamic}}
+	}
+	// The device status is requested a number of times per day.
+	if v := aws.ToInt32(p.DevStatusReqFreq); v > 0 {
+		m.StatusTimePeriodicity = durationpb.New(24 * time.Hour / time.Duration(v))
+	}
+
+	if aws.ToInt32(p.UlRate) > 0 || aws.ToInt32(p.UlBucketSize) > 0 {
+		unsupported = append(unsupported, "UlRate")
+	}
+	if aws.ToInt32(p.DlRate) > 0 || aws.ToInt32(p.DlBucketSize) > 0 {
+		unsupported = append(unsupported, "DlRate")
+	}
+	if aws.ToInt32(p.TargetPer) > 0 {
+		unsupported = append(unsupported, "TargetPer")
+	}
+	if aws.ToInt32(p.MinGwDiversity) > 0 {
+		unsupported = append(unsupported, "MinGwDiversity")
+	}
+	if p.PrAllowed {
+		unsupported = append(unsupported, "PrAllowed")
+	}
+	if p.HrAllowed {
+		unsupported = append(unsupported, "HrAllowed")
+	}
+	if p.RaAllowed {
+		unsupported = append(unsupported, "RaAllowed")
+	}
+	if p.NwkGeoLoc {
+		unsupported = append(unsupported, "NwkGeoLoc")
+	}
+	return unsupported
+}
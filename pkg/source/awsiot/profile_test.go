@@ -0,0 +1,87 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsiot_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iotwireless/types"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/awsiot"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+func TestProfileUnits(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		profile  types.LoRaWANDeviceProfile
+		expected *ttnpb.MACSettings
+	}{
+		{
+			// AWS IoT frequencies are in units of 100 Hz.
+			name: "RxFreq2",
+			profile: types.LoRaWANDeviceProfile{
+				RxFreq2: aws.Int32(8695250),
+			},
+			expected: &ttnpb.MACSettings{
+				Rx2Frequency: &ttnpb.FrequencyValue{Value: 869525000},
+			},
+		},
+		{
+			name: "PingSlotFreq",
+			profile: types.LoRaWANDeviceProfile{
+				PingSlotFreq: aws.Int32(8695250),
+			},
+			expected: &ttnpb.MACSettings{
+				PingSlotFrequency: &ttnpb.ZeroableFrequencyValue{Value: 869525000},
+			},
+		},
+		{
+			// AWS IoT timeouts are in seconds.
+			name: "ClassBTimeout",
+			profile: types.LoRaWANDeviceProfile{
+				ClassBTimeout: aws.Int32(8),
+			},
+			expected: &ttnpb.MACSettings{
+				ClassBTimeout: durationpb.New(8 * time.Second),
+			},
+		},
+		{
+			name: "ClassCTimeout",
+			profile: types.LoRaWANDeviceProfile{
+				ClassCTimeout: aws.Int32(60),
+			},
+			expected: &ttnpb.MACSettings{
+				ClassCTimeout: durationpb.New(time.Minute),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			tc.profile.MacVersion = aws.String("1.0.3")
+			dev := &ttnpb.EndDevice{MacSettings: &ttnpb.MACSettings{}}
+			if err := (awsiot.Profile{LoRaWANDeviceProfile: &tc.profile}).SetFields(dev); !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			tc.expected.Supports_32BitFCnt = &ttnpb.BoolValue{}
+			a.So(dev.MacSettings, should.Resemble, tc.expected)
+		})
+	}
+}
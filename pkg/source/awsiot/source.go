@@ -16,12 +16,16 @@ package awsiot
 
 import (
 	"context"
+	"encoding/hex"
+	"maps"
 	"os"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iotwireless"
 	"github.com/aws/aws-sdk-go-v2/service/iotwireless/types"
 	"go.thethings.network/lorawan-stack-migrate/pkg/iterator"
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source/awsiot/config"
 	"go.thethings.network/lorawan-stack-migrate/pkg/util"
@@ -34,6 +38,28 @@ type Source struct {
 	ctx context.Context
 
 	config *config.Config
+
+	// tags contains the tags of AWS resources by ARN.
+	tags map[string]map[string]string
+	// serviceProfiles contains the service profiles by ID.
+	serviceProfiles map[string]*ServiceProfile
+	// devices contains the devices that are retrieved while ranging devices, until they are exported.
+	devices map[string]cachedDevice
+	// mapped contains the IDs of the devices by application ID, if devices are mapped to applications.
+	mapped *mappedDevices
+	// exported contains the devices that are changed on AWS IoT after they are exported, by DevEUI.
+	exported map[string]*DeviceIdentifiers
+}
+
+type cachedDevice struct {
+	ids *DeviceIdentifiers
+	dev *Device
+}
+
+// mappedDevices contains the IDs of the devices by application ID. deviceIDs is nil until the devices
+// are listed.
+type mappedDevices struct {
+	deviceIDs map[string][]string
 }
 
 func createNewSource(cfg *config.Config) source.CreateSource {
@@ -43,14 +69,24 @@ func createNewSource(cfg *config.Config) source.CreateSource {
 		}
 
 		s := &Source{
-			ctx:    ctx,
-			config: cfg,
+			ctx:             ctx,
+			config:          cfg,
+			tags:            make(map[string]map[string]string),
+			serviceProfiles: make(map[string]*ServiceProfile),
+			devices:         make(map[string]cachedDevice),
+			mapped:          &mappedDevices{},
+			exported:        make(map[string]*DeviceIdentifiers),
 		}
 		return s, nil
 	}
 }
 
+// getDevice returns the device. Devices that are retrieved while ranging devices are only retrieved once.
 func (s Source) getDevice(id string) (*DeviceIdentifiers, *Device, error) {
+	if cached, ok := s.devices[id]; ok {
+		delete(s.devices, id)
+		return cached.ids, cached.dev, nil
+	}
 	resp, err := s.config.Client.GetWirelessDevice(s.ctx, &iotwireless.GetWirelessDeviceInput{
 		IdentifierType: types.WirelessDeviceIdTypeWirelessDeviceId,
 		Identifier:     aws.String(id),
@@ -59,9 +95,12 @@ func (s Source) getDevice(id string) (*DeviceIdentifiers, *Device, error) {
 		return nil, nil, err
 	}
 	deviceIds := &DeviceIdentifiers{
-		Id:          resp.Id,
-		Name:        resp.Name,
-		Description: resp.Description,
+		Id:              resp.Id,
+		Arn:             resp.Arn,
+		Name:            resp.Name,
+		Description:     resp.Description,
+		DestinationName: resp.DestinationName,
+		ThingArn:        resp.ThingArn,
 	}
 	return deviceIds, &Device{resp.LoRaWAN}, nil
 }
@@ -76,6 +115,29 @@ func (s Source) getDeviceProfile(id *string) (*Profile, error) {
 	return &Profile{resp.LoRaWAN}, nil
 }
 
+// getServiceProfile returns the service profile. Settings of the service profile that are not supported
+// are reported when the service profile is retrieved.
+func (s Source) getServiceProfile(id *string) (*ServiceProfile, error) {
+	if profile, ok := s.serviceProfiles[aws.ToString(id)]; ok {
+		return profile, nil
+	}
+	resp, err := s.config.Client.GetServiceProfile(s.ctx, &iotwireless.GetServiceProfileInput{
+		Id: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	profile := &ServiceProfile{resp.LoRaWAN}
+	if unsupported := profile.SetFields(&ttnpb.EndDevice{MacSettings: &ttnpb.MACSettings{}}); len(unsupported) > 0 {
+		s.config.Logger.Warnw("Service profile settings are not supported",
+			"service_profile_id", aws.ToString(id),
+			"settings", unsupported,
+		)
+	}
+	s.serviceProfiles[aws.ToString(id)] = profile
+	return profile, nil
+}
+
 // ExportDevice implements the source.Source interface.
 func (s Source) ExportDevice(devID string) (*ttnpb.EndDevice, error) {
 	devIds, awsDev, err := s.getDevice(devID)
@@ -83,11 +145,19 @@ func (s Source) ExportDevice(devID string) (*ttnpb.EndDevice, error) {
 		return nil, err
 	}
 
+	appID, err := s.applicationID(devIds, *awsDev)
+	if err != nil {
+		return nil, err
+	}
+	if appID == "" {
+		return nil, errNoAppID.WithAttributes("device_id", devID)
+	}
+
 	endDev := &ttnpb.EndDevice{
 		Name:        aws.ToString(devIds.Name),
 		Description: aws.ToString(devIds.Description),
 		Ids: &ttnpb.EndDeviceIdentifiers{
-			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: s.config.AppID},
+			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: appID},
 			DeviceId:       aws.ToString(devIds.Id),
 		},
 		FrequencyPlanId: s.config.FrequencyPlanID,
@@ -106,6 +176,35 @@ func (s Source) ExportDevice(devID string) (*ttnpb.EndDevice, error) {
 	if err := profile.SetFields(endDev); err != nil {
 		return nil, err
 	}
+	if awsDev.ServiceProfileId != nil {
+		serviceProfile, err := s.getServiceProfile(awsDev.ServiceProfileId)
+		if err != nil {
+			return nil, err
+		}
+		serviceProfile.SetFields(endDev)
+	}
+
+	location, err := s.getLocation(devIds.Id)
+	if err != nil {
+		return nil, err
+	}
+	if location != nil {
+		endDev.Locations = map[string]*ttnpb.Location{
+			"user": location,
+		}
+	}
+
+	tags, err := s.getTags(devIds.Arn)
+	if err != nil {
+		return nil, err
+	}
+	var attributes util.Attributes
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		if err := attributes.Set(key, tags[key]); err != nil {
+			s.config.Logger.Warnw("Skip tag", "device_id", devID, "key", key, "error", err)
+		}
+	}
+	endDev.Attributes = attributes.Map()
 
 	if endDev.SupportsJoin {
 		if err := awsDev.SetOTAADevice(endDev); err != nil {
@@ -115,48 +214,174 @@ func (s Source) ExportDevice(devID string) (*ttnpb.EndDevice, error) {
 		if err := awsDev.SetABPDevice(endDev); err != nil {
 			return nil, err
 		}
+		// Create a MACState.
+		if endDev.MacState, err = session.NewMACState(endDev, s.config.FPStore()); err != nil {
+			return nil, err
+		}
+	}
+	if s.config.DeleteSourceDevice || s.config.DisassociateSourceDevice {
+		// The source device is changed after the device is exported.
+		s.exported[hex.EncodeToString(endDev.Ids.DevEui)] = devIds
 	}
 	return endDev, nil
 }
 
+// DeviceExported implements the source.ExportedDeviceHandler interface.
+// The source device is deleted or disassociated after the device is exported.
+func (s Source) DeviceExported(dev *ttnpb.EndDevice) error {
+	key := hex.EncodeToString(dev.Ids.DevEui)
+	devIds, ok := s.exported[key]
+	if !ok {
+		return nil
+	}
+	delete(s.exported, key)
+	switch {
+	case s.config.DeleteSourceDevice:
+		return s.deleteDevice(devIds)
+	case s.config.DisassociateSourceDevice:
+		return s.disassociateDevice(devIds)
+	}
+	return nil
+}
+
+// disassociateDevice disassociates the device from its AWS IoT thing.
+func (s Source) disassociateDevice(devIds *DeviceIdentifiers) error {
+	if devIds.ThingArn == nil {
+		return nil
+	}
+	s.config.Logger.Infow("Disassociating device from thing",
+		"device_id", aws.ToString(devIds.Id),
+		"thing_arn", aws.ToString(devIds.ThingArn),
+	)
+	_, err := s.config.Client.DisassociateWirelessDeviceFromThing(s.ctx, &iotwireless.DisassociateWirelessDeviceFromThingInput{
+		Id: devIds.Id,
+	})
+	return err
+}
+
+// deleteDevice deletes the device from AWS IoT.
+func (s Source) deleteDevice(devIds *DeviceIdentifiers) error {
+	s.config.Logger.Infow("Deleting device", "device_id", aws.ToString(devIds.Id))
+	_, err := s.config.Client.DeleteWirelessDevice(s.ctx, &iotwireless.DeleteWirelessDeviceInput{
+		Id: devIds.Id,
+	})
+	return err
+}
+
 // Iterator implements source.Source.
-func (s Source) Iterator(bool) iterator.Iterator {
+func (s Source) Iterator(isApplication bool) iterator.Iterator {
+	if isApplication && len(s.config.AppIDs) > 0 {
+		return iterator.NewListIterator(s.applicationIDs())
+	}
 	return iterator.NewReaderIterator(os.Stdin, '\n')
 }
 
-func (s Source) rangeThings(things []types.WirelessDeviceStatistics, f func(source.Source, string) error) error {
-	for _, t := range things {
-		id := aws.ToString(t.Id)
-		if err := f(s, id); err != nil {
+// RangeDevices implements the source.Source interface.
+// Devices are filtered by the configured destination, profiles and tags. If devices are mapped
+// to applications, only the devices of the given application are exported. Otherwise, the application
+// is the configured application ID, or a key of the mapping file to filter the devices by.
+func (s Source) RangeDevices(appID string, f func(source.Source, string) error) error {
+	filter := deviceFilter{
+		destinationName:  s.config.DestinationName,
+		deviceProfileID:  s.config.DeviceProfileID,
+		serviceProfileID: s.config.ServiceProfileID,
+		tags:             s.config.Tags,
+	}
+	if len(s.config.AppIDs) > 0 {
+		deviceIDs, err := s.mappedDeviceIDs(filter)
+		if err != nil {
 			return err
 		}
+		for _, id := range deviceIDs[appID] {
+			if err := f(s, id); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
-	return nil
+	if appID != s.config.AppID {
+		var err error
+		if filter, err = filter.withKey(appID); err != nil {
+			return err
+		}
+	}
+	return s.listDevices(filter, func(t types.WirelessDeviceStatistics) error {
+		ok, err := s.matchesTags(t.Arn, filter.tags)
+		if err != nil || !ok {
+			return err
+		}
+		return f(s, aws.ToString(t.Id))
+	})
 }
 
-// RangeDevices implements the source.Source interface.
-func (s Source) RangeDevices(appID string, f func(source.Source, string) error) error {
-	resp, err := s.config.Client.ListWirelessDevices(s.ctx,
-		&iotwireless.ListWirelessDevicesInput{
-			WirelessDeviceType: types.WirelessDeviceTypeLoRaWAN,
-			MaxResults:         100,
-		})
-	if err != nil {
-		return err
-	}
-	s.rangeThings(resp.WirelessDeviceList, f)
-	for resp.NextToken != nil {
-		resp, err = s.config.Client.ListWirelessDevices(s.ctx,
-			&iotwireless.ListWirelessDevicesInput{
-				NextToken:  resp.NextToken,
-				MaxResults: 100,
-			})
+// listDevices calls f for the devices that match the destination and profiles of the filter.
+func (s Source) listDevices(filter deviceFilter, f func(types.WirelessDeviceStatistics) error) error {
+	input := &iotwireless.ListWirelessDevicesInput{
+		WirelessDeviceType: types.WirelessDeviceTypeLoRaWAN,
+		MaxResults:         100,
+		DestinationName:    optionalString(filter.destinationName),
+		DeviceProfileId:    optionalString(filter.deviceProfileID),
+		ServiceProfileId:   optionalString(filter.serviceProfileID),
+	}
+	for {
+		resp, err := s.config.Client.ListWirelessDevices(s.ctx, input)
 		if err != nil {
 			return err
 		}
-		s.rangeThings(resp.WirelessDeviceList, f)
+		for _, t := range resp.WirelessDeviceList {
+			if err := f(t); err != nil {
+				return err
+			}
+		}
+		if resp.NextToken == nil {
+			return nil
+		}
+		input.NextToken = resp.NextToken
 	}
-	return nil
+}
+
+// mappedDeviceIDs returns the IDs of the devices that match the filter, by application ID.
+// The devices are listed and mapped to applications once. Mapped devices are cached, so that
+// they are not retrieved again when they are exported.
+func (s Source) mappedDeviceIDs(filter deviceFilter) (map[string][]string, error) {
+	if s.mapped.deviceIDs != nil {
+		return s.mapped.deviceIDs, nil
+	}
+	deviceIDs := make(map[string][]string)
+	err := s.listDevices(filter, func(t types.WirelessDeviceStatistics) error {
+		ok, err := s.matchesTags(t.Arn, filter.tags)
+		if err != nil || !ok {
+			return err
+		}
+		id := aws.ToString(t.Id)
+		devIds, awsDev, err := s.getDevice(id)
+		if err != nil {
+			return err
+		}
+		appID, err := s.applicationID(devIds, *awsDev)
+		if err != nil {
+			return err
+		}
+		if appID == "" {
+			s.config.Logger.Warnw("Device is not mapped to an application", "device_id", id)
+			return nil
+		}
+		s.devices[id] = cachedDevice{ids: devIds, dev: awsDev}
+		deviceIDs[appID] = append(deviceIDs[appID], id)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.mapped.deviceIDs = deviceIDs
+	return deviceIDs, nil
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
 }
 
 // Close implements the source.Source interface.
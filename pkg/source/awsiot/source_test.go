@@ -0,0 +1,311 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsiot_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/export"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	_ "go.thethings.network/lorawan-stack-migrate/pkg/source/awsiot"
+)
+
+type fakeDevice struct {
+	destination string
+	devEUI      string
+	tags        map[string]string
+}
+
+var fakeDevices = map[string]fakeDevice{
+	"dev-1": {destination: "building-a", devEUI: "0102030405060701", tags: map[string]string{"site": "berlin"}},
+	"dev-2": {destination: "building-b", devEUI: "0102030405060702", tags: map[string]string{"site": "paris"}},
+	"dev-3": {destination: "other", devEUI: "0102030405060703"},
+}
+
+func deviceARN(id string) string {
+	return "arn:aws:iotwireless:eu-west-1:123456789012:WirelessDevice/" + id
+}
+
+// fakeServer is a fake AWS IoT Wireless API.
+type fakeServer struct {
+	mu sync.Mutex
+	// gets contains the number of times that each device is retrieved.
+	gets          map[string]int
+	deleted       []string
+	disassociated []string
+}
+
+func (s *fakeServer) handler() http.Handler {
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v) //nolint:errcheck
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /wireless-devices", func(w http.ResponseWriter, r *http.Request) {
+		devices := []map[string]any{}
+		for _, id := range slices.Sorted(maps.Keys(fakeDevices)) {
+			dev := fakeDevices[id]
+			if name := r.URL.Query().Get("destinationName"); name != "" && name != dev.destination {
+				continue
+			}
+			devices = append(devices, map[string]any{
+				"Id":              id,
+				"Arn":             deviceARN(id),
+				"DestinationName": dev.destination,
+				"Type":            "LoRaWAN",
+				"LoRaWAN":         map[string]any{"DevEui": dev.devEUI},
+			})
+		}
+		writeJSON(w, map[string]any{"WirelessDeviceList": devices})
+	})
+	mux.HandleFunc("GET /wireless-devices/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		dev, ok := fakeDevices[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		s.mu.Lock()
+		s.gets[id]++
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{
+			"Id":              id,
+			"Arn":             deviceARN(id),
+			"Name":            id,
+			"DestinationName": dev.destination,
+			"ThingArn":        "arn:aws:iot:eu-west-1:123456789012:thing/" + id,
+			"Type":            "LoRaWAN",
+			"LoRaWAN": map[string]any{
+				"DevEui":          dev.devEUI,
+				"DeviceProfileId": "profile",
+				"OtaaV1_0_x": map[string]any{
+					"AppKey": "01020304050607080102030405060708",
+					"AppEui": "0807060504030201",
+				},
+			},
+		})
+	})
+	mux.HandleFunc("DELETE /wireless-devices/{id}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.deleted = append(s.deleted, r.PathValue("id"))
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /wireless-devices/{id}/thing", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.disassociated = append(s.disassociated, r.PathValue("id"))
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /device-profiles/{id}", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"Id": r.PathValue("id"),
+			"LoRaWAN": map[string]any{
+				"MacVersion":   "1.0.3",
+				"SupportsJoin": true,
+			},
+		})
+	})
+	mux.HandleFunc("GET /tags", func(w http.ResponseWriter, r *http.Request) {
+		tags := []map[string]string{}
+		for id, dev := range fakeDevices {
+			if deviceARN(id) != r.URL.Query().Get("resourceArn") {
+				continue
+			}
+			for key, value := range dev.tags {
+				tags = append(tags, map[string]string{"Key": key, "Value": value})
+			}
+		}
+		writeJSON(w, map[string]any{"Tags": tags})
+	})
+	// The devices have no position.
+	mux.HandleFunc("GET /resource-positions/{id}", func(http.ResponseWriter, *http.Request) {})
+	return mux
+}
+
+// newSource returns an AWS IoT source that uses the fake server, with the flags set.
+func newSource(t *testing.T, flagValues map[string]string) (source.Source, *fakeServer) {
+	t.Helper()
+	fake := &fakeServer{gets: make(map[string]int)}
+	server := httptest.NewServer(fake.handler())
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	flags, err := source.FlagSet("awsiot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{
+		"endpoint-url":         server.URL,
+		"region":               "eu-west-1",
+		"frequency-plan-id":    "EU_863_870",
+		"app-id":               "",
+		"app-id-mapping-file":  "",
+		"delete-source-device": "false",
+		"disassociate-thing":   "false",
+	}
+	for name, value := range flagValues {
+		values[name] = value
+	}
+	for name, value := range values {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source.RootConfig.SetSource("awsiot")
+	source.RootConfig.FrequencyPlansURL = "https://raw.githubusercontent.com/TheThingsNetwork/lorawan-frequency-plans/master"
+	src, err := source.NewSource(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	t.Cleanup(func() { src.Close() })
+	return src, fake
+}
+
+func rangeDeviceIDs(src source.Source, appID string) ([]string, error) {
+	var ids []string
+	err := src.RangeDevices(appID, func(_ source.Source, id string) error {
+		ids = append(ids, id)
+		return nil
+	})
+	return ids, err
+}
+
+func TestRangeMappedDevices(t *testing.T) {
+	a := assertions.New(t)
+	mappingPath := filepath.Join(t.TempDir(), "mapping.csv")
+	if err := os.WriteFile(mappingPath, []byte("destination:building-a,app-a\ndestination:building-b,app-b\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	src, fake := newSource(t, map[string]string{"app-id-mapping-file": mappingPath})
+
+	for _, tc := range []struct {
+		appID string
+		ids   []string
+	}{
+		{appID: "app-a", ids: []string{"dev-1"}},
+		{appID: "app-b", ids: []string{"dev-2"}},
+		{appID: "app-c"},
+	} {
+		ids, err := rangeDeviceIDs(src, tc.appID)
+		a.So(err, should.BeNil)
+		a.So(ids, should.Resemble, tc.ids)
+	}
+
+	// Each device is retrieved once to map it to an application, including the device that is not mapped.
+	fake.mu.Lock()
+	a.So(fake.gets, should.Resemble, map[string]int{"dev-1": 1, "dev-2": 1, "dev-3": 1})
+	fake.mu.Unlock()
+
+	// Mapped devices are not retrieved again when they are exported.
+	dev, err := src.ExportDevice("dev-1")
+	if !a.So(err, should.BeNil) {
+		t.FailNow()
+	}
+	a.So(dev.Ids.ApplicationIds.ApplicationId, should.Equal, "app-a")
+	fake.mu.Lock()
+	a.So(fake.gets["dev-1"], should.Equal, 1)
+	fake.mu.Unlock()
+}
+
+func TestRangeFilteredDevices(t *testing.T) {
+	src, fake := newSource(t, map[string]string{"app-id": "my-app"})
+
+	for _, tc := range []struct {
+		appID   string
+		ids     []string
+		invalid bool
+	}{
+		{appID: "my-app", ids: []string{"dev-1", "dev-2", "dev-3"}},
+		{appID: "tag:site=berlin", ids: []string{"dev-1"}},
+		{appID: "destination:building-b", ids: []string{"dev-2"}},
+		{appID: "tag:site", invalid: true},
+		{appID: "other-app", invalid: true},
+	} {
+		t.Run(tc.appID, func(t *testing.T) {
+			a := assertions.New(t)
+			ids, err := rangeDeviceIDs(src, tc.appID)
+			if tc.invalid {
+				a.So(err, should.NotBeNil)
+				return
+			}
+			a.So(err, should.BeNil)
+			a.So(ids, should.Resemble, tc.ids)
+		})
+	}
+
+	// Devices are not retrieved while ranging devices without mapping.
+	fake.mu.Lock()
+	assertions.New(t).So(fake.gets, should.BeEmpty)
+	fake.mu.Unlock()
+}
+
+func TestDeviceExported(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		flag          string
+		devIDPrefix   string
+		deleted       []string
+		disassociated []string
+	}{
+		{
+			name:    "Delete",
+			flag:    "delete-source-device",
+			deleted: []string{"dev-1"},
+		},
+		{
+			name:          "Disassociate",
+			flag:          "disassociate-thing",
+			disassociated: []string{"dev-1"},
+		},
+		{
+			// The device ID is too long, so the device is not exported and not deleted.
+			name:        "NotExported",
+			flag:        "delete-source-device",
+			devIDPrefix: "a-prefix-that-makes-the-device-id-too-long",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			src, fake := newSource(t, map[string]string{"app-id": "my-app", tc.flag: "true"})
+
+			err := export.Config{DevIDPrefix: tc.devIDPrefix}.ExportDev(src, "dev-1")
+			if tc.devIDPrefix != "" {
+				a.So(err, should.NotBeNil)
+			} else {
+				a.So(err, should.BeNil)
+			}
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			a.So(fake.deleted, should.Resemble, tc.deleted)
+			a.So(fake.disassociated, should.Resemble, tc.disassociated)
+		})
+	}
+}
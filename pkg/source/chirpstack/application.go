@@ -0,0 +1,122 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"strings"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+// httpIntegrationWebhookID is the webhook ID of the exported ChirpStack HTTP integration.
+const httpIntegrationWebhookID = "chirpstack-http"
+
+// ExportApplicationSettings implements the source.ApplicationSettingsSource interface.
+func (p *Source) ExportApplicationSettings(application string) (*source.ApplicationSettings, error) {
+	csapp, err := p.getApplication(application)
+	if err != nil {
+		return nil, err
+	}
+	appID, err := p.applicationID(csapp.Id)
+	if err != nil {
+		return nil, err
+	}
+	appIDs := &ttnpb.ApplicationIdentifiers{ApplicationId: appID}
+
+	settings := &source.ApplicationSettings{
+		Application: &ttnpb.Application{
+			Ids:         appIDs,
+			Name:        csapp.Name,
+			Description: csapp.Description,
+		},
+	}
+	attributes := util.Attributes{}
+	p.setAttributes(&attributes, map[string]string{"chirpstack-application-id": csapp.Id}, "application_id", csapp.Id)
+	p.setAttributes(&attributes, csapp.Tags, "application_id", csapp.Id)
+	settings.Application.Attributes = attributes.Map()
+	if p.FormattersOnApplication {
+		formatters, err := p.getApplicationFormatters(csapp.Id)
+		if err != nil {
+			return nil, err
+		}
+		if formatters != nil {
+			settings.Link = &ttnpb.ApplicationLink{DefaultFormatters: formatters}
+		}
+	}
+
+	client := csv4api.NewApplicationServiceClient(p.ClientConn)
+	integrations, err := client.ListIntegrations(p.ctx, &csv4api.ListIntegrationsRequest{
+		ApplicationId: csapp.Id,
+	})
+	if err != nil {
+		return nil, errAPI.WithCause(err)
+	}
+	for _, integration := range integrations.Result {
+		switch integration.Kind {
+		case csv4api.IntegrationKind_HTTP:
+			resp, err := client.GetHttpIntegration(p.ctx, &csv4api.GetHttpIntegrationRequest{
+				ApplicationId: csapp.Id,
+			})
+			if err != nil {
+				return nil, errAPI.WithCause(err)
+			}
+			settings.Webhooks = append(settings.Webhooks, httpIntegrationToWebhook(appIDs, resp.Integration))
+		case csv4api.IntegrationKind_MQTT_GLOBAL:
+			p.logger.Infow("ChirpStack MQTT integration is not exported. Use the MQTT server of The Things Stack instead",
+				"application_id", csapp.Id,
+			)
+		default:
+			p.logger.Warnw("ChirpStack integration cannot be exported and needs to be recreated manually",
+				"application_id", csapp.Id,
+				"integration", integration.Kind.String(),
+			)
+		}
+	}
+	return settings, nil
+}
+
+// httpIntegrationToWebhook converts a ChirpStack HTTP integration to a webhook.
+// ChirpStack posts all events to the same endpoint with the event type in the `event` query parameter,
+// so every message type is configured with a path that only sets the query parameter.
+func httpIntegrationToWebhook(appIDs *ttnpb.ApplicationIdentifiers, integration *csv4api.HttpIntegration) *ttnpb.ApplicationWebhook {
+	format := "json"
+	if integration.Encoding == csv4api.Encoding_PROTOBUF {
+		format = "protobuf"
+	}
+	separator := "?"
+	if strings.Contains(integration.EventEndpointUrl, "?") {
+		separator = "&"
+	}
+	message := func(event string) *ttnpb.ApplicationWebhook_Message {
+		return &ttnpb.ApplicationWebhook_Message{Path: separator + "event=" + event}
+	}
+	return &ttnpb.ApplicationWebhook{
+		Ids: &ttnpb.ApplicationWebhookIdentifiers{
+			ApplicationIds: appIDs,
+			WebhookId:      httpIntegrationWebhookID,
+		},
+		BaseUrl:        integration.EventEndpointUrl,
+		Headers:        integration.Headers,
+		Format:         format,
+		UplinkMessage:  message("up"),
+		JoinAccept:     message("join"),
+		DownlinkAck:    message("ack"),
+		DownlinkSent:   message("txack"),
+		LocationSolved: message("location"),
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package codec translates ChirpStack codec scripts to The Things Stack payload formatters.
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Flavor is the flavor of a ChirpStack codec script.
+type Flavor int
+
+const (
+	// FlavorUnknown is a script without known codec functions.
+	FlavorUnknown Flavor = iota
+	// FlavorV3 is a ChirpStack v3 codec with Decode(fPort, bytes, variables) and Encode(fPort, obj, variables) functions.
+	FlavorV3
+	// FlavorV4 is a ChirpStack v4 codec with decodeUplink(input) and encodeDownlink(input) functions.
+	FlavorV4
+)
+
+// String implements fmt.Stringer.
+func (f Flavor) String() string {
+	switch f {
+	case FlavorV3:
+		return "v3"
+	case FlavorV4:
+		return "v4"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	v3Decode         = regexp.MustCompile(`\bfunction\s+Decode\s*\(|\bDecode\s*=\s*function\b`)
+	v3Encode         = regexp.MustCompile(`\bfunction\s+Encode\s*\(|\bEncode\s*=\s*function\b`)
+	v4DecodeUplink   = regexp.MustCompile(`\bfunction\s+decodeUplink\s*\(|\bdecodeUplink\s*=`)
+	v4EncodeDownlink = regexp.MustCompile(`\bfunction\s+encodeDownlink\s*\(|\bencodeDownlink\s*=`)
+)
+
+// The original script is wrapped in a function scope, so that its functions do not clash with the
+// functions that The Things Stack calls. The device variables are passed to the codec functions.
+const (
+	scriptFormat = `var variables = %s;
+
+var chirpstack = (function () {
+%s
+
+return {
+	%s: typeof %[3]s === "function" ? %[3]s : undefined,
+	%s: typeof %[4]s === "function" ? %[4]s : undefined,
+};
+})();`
+
+	v3UplinkFormat = `%s
+
+function decodeUplink(input) {
+	return { data: chirpstack.Decode(input.fPort, input.bytes, variables) };
+}`
+	// The Things Stack does not pass the FPort to encodeDownlink, so the legacy Encoder function is used instead.
+	v3DownlinkFormat = `%s
+
+function Encoder(payload, fport) {
+	return chirpstack.Encode(fport, payload, variables);
+}`
+	v4UplinkFormat = `%s
+
+function decodeUplink(input) {
+	return chirpstack.decodeUplink({
+		bytes: input.bytes,
+		fPort: input.fPort,
+		recvTime: input.recvTime,
+		variables: variables,
+	});
+}`
+	v4DownlinkFormat = `%s
+
+function encodeDownlink(input) {
+	return chirpstack.encodeDownlink({
+		data: input.data,
+		variables: variables,
+	});
+}`
+)
+
+// Scripts are The Things Stack JavaScript payload formatters.
+// Uplink or Downlink is empty if the codec does not support the direction.
+type Scripts struct {
+	Flavor   Flavor
+	Uplink   string
+	Downlink string
+}
+
+// Detect detects the flavor of a ChirpStack codec script.
+// ChirpStack v4 functions take precedence over v3 functions.
+func Detect(script string) Flavor {
+	switch {
+	case v4DecodeUplink.MatchString(script) || v4EncodeDownlink.MatchString(script):
+		return FlavorV4
+	case v3Decode.MatchString(script) || v3Encode.MatchString(script):
+		return FlavorV3
+	default:
+		return FlavorUnknown
+	}
+}
+
+// Translate translates a ChirpStack codec script to The Things Stack payload formatters.
+// The variables are available to the codec functions as they are in ChirpStack.
+// Translate returns false if the flavor of the script is unknown.
+func Translate(script string, variables map[string]string) (Scripts, bool) {
+	if variables == nil {
+		variables = make(map[string]string)
+	}
+	vars, err := json.Marshal(variables)
+	if err != nil {
+		// A map of strings is always marshaled successfully.
+		panic(err)
+	}
+
+	flavor := Detect(script)
+	scripts := Scripts{Flavor: flavor}
+	switch flavor {
+	case FlavorV3:
+		wrapped := fmt.Sprintf(scriptFormat, vars, script, "Decode", "Encode")
+		if v3Decode.MatchString(script) {
+			scripts.Uplink = fmt.Sprintf(v3UplinkFormat, wrapped)
+		}
+		if v3Encode.MatchString(script) {
+			scripts.Downlink = fmt.Sprintf(v3DownlinkFormat, wrapped)
+		}
+	case FlavorV4:
+		wrapped := fmt.Sprintf(scriptFormat, vars, script, "decodeUplink", "encodeDownlink")
+		if v4DecodeUplink.MatchString(script) {
+			scripts.Uplink = fmt.Sprintf(v4UplinkFormat, wrapped)
+		}
+		if v4EncodeDownlink.MatchString(script) {
+			scripts.Downlink = fmt.Sprintf(v4DownlinkFormat, wrapped)
+		}
+	default:
+		return scripts, false
+	}
+	return scripts, true
+}
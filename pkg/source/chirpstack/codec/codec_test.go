@@ -0,0 +1,139 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec_test
+
+import (
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/chirpstack/codec"
+)
+
+const (
+	v3Script = `function Decode(fPort, bytes, variables) {
+	return { temperature: bytes[0] };
+}
+
+function Encode(fPort, obj, variables) {
+	return [obj.setpoint];
+}`
+	v4Script = `function decodeUplink(input) {
+	return { data: { temperature: input.bytes[0] } };
+}
+
+function encodeDownlink(input) {
+	return { bytes: [input.data.setpoint] };
+}`
+	v4UplinkOnlyScript = `function decodeUplink(input) {
+	return { data: { temperature: input.bytes[0] } };
+}`
+)
+
+func TestDetect(t *testing.T) {
+	a := assertions.New(t)
+
+	a.So(codec.Detect(v3Script), should.Equal, codec.FlavorV3)
+	a.So(codec.Detect(v4Script), should.Equal, codec.FlavorV4)
+	a.So(codec.Detect(v4UplinkOnlyScript), should.Equal, codec.FlavorV4)
+	a.So(codec.Detect("var Decode = function (fPort, bytes) { return {}; };"), should.Equal, codec.FlavorV3)
+	a.So(codec.Detect("function Decoder(bytes, port) { return {}; }"), should.Equal, codec.FlavorUnknown)
+	a.So(codec.Detect(""), should.Equal, codec.FlavorUnknown)
+}
+
+func TestTranslate(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		script        string
+		variables     map[string]string
+		ok            bool
+		flavor        codec.Flavor
+		uplink        []string
+		downlink      []string
+		emptyUplink   bool
+		emptyDownlink bool
+	}{
+		{
+			name:   "V3",
+			script: v3Script,
+			ok:     true,
+			flavor: codec.FlavorV3,
+			uplink: []string{
+				"var variables = {};",
+				"function decodeUplink(input) {",
+				"chirpstack.Decode(input.fPort, input.bytes, variables)",
+			},
+			downlink: []string{
+				"function Encoder(payload, fport) {",
+				"chirpstack.Encode(fport, payload, variables)",
+			},
+		},
+		{
+			name:      "V4",
+			script:    v4Script,
+			variables: map[string]string{"offset": "10"},
+			ok:        true,
+			flavor:    codec.FlavorV4,
+			uplink: []string{
+				`var variables = {"offset":"10"};`,
+				"return chirpstack.decodeUplink({",
+			},
+			downlink: []string{
+				`var variables = {"offset":"10"};`,
+				"function encodeDownlink(input) {",
+				"return chirpstack.encodeDownlink({",
+			},
+		},
+		{
+			name:          "V4UplinkOnly",
+			script:        v4UplinkOnlyScript,
+			ok:            true,
+			flavor:        codec.FlavorV4,
+			uplink:        []string{"return chirpstack.decodeUplink({"},
+			emptyDownlink: true,
+		},
+		{
+			name:          "Unknown",
+			script:        "function Decoder(bytes, port) { return {}; }",
+			flavor:        codec.FlavorUnknown,
+			emptyUplink:   true,
+			emptyDownlink: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+
+			scripts, ok := codec.Translate(tc.script, tc.variables)
+			a.So(ok, should.Equal, tc.ok)
+			a.So(scripts.Flavor, should.Equal, tc.flavor)
+			if tc.emptyUplink {
+				a.So(scripts.Uplink, should.BeEmpty)
+			} else {
+				a.So(scripts.Uplink, should.ContainSubstring, tc.script)
+			}
+			if tc.emptyDownlink {
+				a.So(scripts.Downlink, should.BeEmpty)
+			} else {
+				a.So(scripts.Downlink, should.ContainSubstring, tc.script)
+			}
+			for _, s := range tc.uplink {
+				a.So(scripts.Uplink, should.ContainSubstring, s)
+			}
+			for _, s := range tc.downlink {
+				a.So(scripts.Downlink, should.ContainSubstring, s)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/spf13/pflag"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
 	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
 	"go.thethings.network/lorawan-stack/v3/pkg/types"
@@ -34,20 +35,44 @@ import (
 
 const dialTimeout = 10 * time.Second
 
+// ApplicationIDFormat is the format of the application IDs of exported applications.
+type ApplicationIDFormat string
+
+const (
+	// ApplicationIDFormatUUIDSuffix uses the last part of the ChirpStack application ID (UUID).
+	ApplicationIDFormatUUIDSuffix ApplicationIDFormat = "uuid-suffix"
+	// ApplicationIDFormatNameSlug uses the ChirpStack application name, converted to a valid application ID.
+	ApplicationIDFormatNameSlug ApplicationIDFormat = "name-slug"
+)
+
 type Config struct {
 	src source.Config
 
-	apiKey, caCertPath, url, joinEUI string
-	flags                            *pflag.FlagSet
-	FPStore                          *frequencyplans.Store
-	insecure                         bool
+	apiKey, caCertPath, url, joinEUI,
+	appIDFormat, appIDMappingPath string
+	flags    *pflag.FlagSet
+	FPStore  *frequencyplans.Store
+	insecure bool
 
 	ClientConn *grpc.ClientConn
 
 	ExportVars,
-	ExportSession bool
-	FrequencyPlanID string
-	JoinEUI         *types.EUI64
+	ExportSession,
+	ExportMulticastGroups,
+	FormattersOnApplication,
+	AllApplications,
+	DisableSourceDevice,
+	InvalidateKeys,
+	Rollback bool
+	FrequencyPlanID           string
+	JoinEUI                   *types.EUI64
+	TenantID                  string
+	MulticastGroupsReportPath string
+	DownlinkQueuePath         string
+
+	ApplicationIDFormat ApplicationIDFormat
+	// ApplicationIDs maps ChirpStack application IDs (UUID) or names to application IDs.
+	ApplicationIDs map[string]string
 }
 
 func New() *Config {
@@ -82,11 +107,57 @@ func New() *Config {
 	config.flags.StringVar(&config.joinEUI,
 		"join-eui",
 		os.Getenv("JOIN_EUI"),
-		"JoinEUI of exported devices")
+		"(optional) JoinEUI of exported devices that have no JoinEUI in ChirpStack")
 	config.flags.StringVar(&config.FrequencyPlanID,
 		"frequency-plan-id",
 		os.Getenv("FREQUENCY_PLAN_ID"),
 		"Frequency Plan ID of exported devices")
+	config.flags.StringVar(&config.TenantID,
+		"tenant-id",
+		os.Getenv("CHIRPSTACK_TENANT_ID"),
+		"(optional) ChirpStack tenant ID (UUID) to filter applications and gateways on")
+	config.flags.BoolVar(&config.AllApplications,
+		"all-applications",
+		os.Getenv("CHIRPSTACK_ALL_APPLICATIONS") == "true",
+		"Export all applications of the tenant")
+	config.flags.StringVar(&config.appIDFormat,
+		"application-id-format",
+		os.Getenv("CHIRPSTACK_APPLICATION_ID_FORMAT"),
+		"(optional) Format of the IDs of exported applications (uuid-suffix, name-slug). Defaults to uuid-suffix")
+	config.flags.StringVar(&config.appIDMappingPath,
+		"application-id-mapping-file",
+		os.Getenv("CHIRPSTACK_APPLICATION_ID_MAPPING_FILE"),
+		"(optional) Path to a file that maps ChirpStack application IDs or names to application IDs, one pair per line")
+	config.flags.BoolVar(&config.ExportMulticastGroups,
+		"export-multicast-groups",
+		os.Getenv("EXPORT_MULTICAST_GROUPS") == "true",
+		"Export the multicast groups of exported applications as multicast end devices")
+	config.flags.StringVar(&config.MulticastGroupsReportPath,
+		"multicast-groups-report",
+		os.Getenv("MULTICAST_GROUPS_REPORT"),
+		"(optional) Path to a file to write the member devices of exported multicast groups to")
+	config.flags.StringVar(&config.DownlinkQueuePath,
+		"downlink-queue-file",
+		os.Getenv("DOWNLINK_QUEUE_FILE"),
+		"(optional) Path to a file to write the downlink queue of exported devices to")
+	config.flags.BoolVar(&config.FormattersOnApplication,
+		"formatters-on-application",
+		os.Getenv("FORMATTERS_ON_APPLICATION") == "true",
+		"Export the most used payload formatters of an application as default formatters of the application, instead of setting them on every device")
+	config.flags.BoolVar(&config.DisableSourceDevice,
+		"disable-source-device",
+		os.Getenv("DISABLE_SOURCE_DEVICE") == "true",
+		"Disable the exported devices on ChirpStack")
+	config.flags.BoolVar(&config.InvalidateKeys,
+		"invalidate-keys",
+		os.Getenv("INVALIDATE_KEYS") == "true",
+		`Invalidate the root keys of the exported devices on ChirpStack.
+This is necessary to prevent both networks from answering join requests of the same device.
+The last byte of the keys will be incremented by 0x01. This enables an easy rollback if necessary`)
+	config.flags.BoolVar(&config.Rollback,
+		"rollback",
+		os.Getenv("ROLLBACK") == "true",
+		"Restore the keys and enable the devices on ChirpStack that were invalidated or disabled by a previous export")
 
 	return config
 }
@@ -103,15 +174,39 @@ func (c *Config) Initialize(src source.Config) error {
 	if c.url == "" {
 		return errNoAPIURL.New()
 	}
-	if c.FrequencyPlanID == "" {
+	if c.FrequencyPlanID == "" && src.Entity != source.EntityApplicationSettings {
 		return errNoFrequencyPlan.New()
 	}
-	if c.joinEUI == "" {
-		return errNoJoinEUI.New()
+	if c.Rollback && (c.DisableSourceDevice || c.InvalidateKeys) {
+		return errRollbackConflict.New()
+	}
+	// Source devices are not updated during a dry run.
+	if src.DryRun && (c.DisableSourceDevice || c.InvalidateKeys || c.Rollback) {
+		src.Logger.Warn("Cannot update source devices during a dry run.")
+		c.DisableSourceDevice, c.InvalidateKeys, c.Rollback = false, false, false
+	}
+	if c.AllApplications && c.TenantID == "" {
+		return errNoTenantID.New()
 	}
-	c.JoinEUI = &types.EUI64{}
-	if err := c.JoinEUI.UnmarshalText([]byte(c.joinEUI)); err != nil {
-		return errInvalidJoinEUI.WithAttributes("join_eui", c.joinEUI)
+	switch format := ApplicationIDFormat(c.appIDFormat); format {
+	case "":
+		c.ApplicationIDFormat = ApplicationIDFormatUUIDSuffix
+	case ApplicationIDFormatUUIDSuffix, ApplicationIDFormatNameSlug:
+		c.ApplicationIDFormat = format
+	default:
+		return errInvalidApplicationIDFormat.WithAttributes("format", c.appIDFormat)
+	}
+	if c.appIDMappingPath != "" {
+		var err error
+		if c.ApplicationIDs, err = util.ReadMappingFile(c.appIDMappingPath); err != nil {
+			return err
+		}
+	}
+	if src.Entity == source.EntityEndDevices && c.joinEUI != "" {
+		c.JoinEUI = &types.EUI64{}
+		if err := c.JoinEUI.UnmarshalText([]byte(c.joinEUI)); err != nil {
+			return errInvalidJoinEUI.WithAttributes("join_eui", c.joinEUI)
+		}
 	}
 
 	err := c.dialGRPC(
@@ -20,7 +20,10 @@ var (
 	errNoAPIToken      = errors.DefineInvalidArgument("no_api_token", "no API token")
 	errNoAPIURL        = errors.DefineInvalidArgument("no_api_url", "no API URL")
 	errNoFrequencyPlan = errors.DefineInvalidArgument("no_frequency_plan", "no Frequency Plan")
-	errNoJoinEUI       = errors.DefineInvalidArgument("no_join_eui", "no join eui")
+	errNoTenantID      = errors.DefineInvalidArgument("no_tenant_id", "no tenant ID, required to export all applications")
 
-	errInvalidJoinEUI = errors.DefineInvalidArgument("invalid_join_eui", "invalid JoinEUI `{join_eui}`")
+	errInvalidJoinEUI             = errors.DefineInvalidArgument("invalid_join_eui", "invalid JoinEUI `{join_eui}`")
+	errInvalidApplicationIDFormat = errors.DefineInvalidArgument("invalid_application_id_format", "invalid application ID format `{format}`")
+
+	errRollbackConflict = errors.DefineInvalidArgument("rollback_conflict", "rollback can not be combined with disabling source devices or invalidating keys")
 )
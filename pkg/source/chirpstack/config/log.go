@@ -20,6 +20,15 @@ func (c *Config) LogFields() log.Fielder {
 	return log.Fields(
 		"export_vars", c.ExportVars,
 		"export_session", c.ExportSession,
+		"export_multicast_groups", c.ExportMulticastGroups,
+		"formatters_on_application", c.FormattersOnApplication,
+		"all_applications", c.AllApplications,
+		"disable_source_device", c.DisableSourceDevice,
+		"invalidate_keys", c.InvalidateKeys,
+		"rollback", c.Rollback,
+		"application_id_format", c.ApplicationIDFormat,
+		"tenant_id", c.TenantID,
+		"downlink_queue_file", c.DownlinkQueuePath,
 		"insecure", c.insecure,
 		"url", c.url,
 	)
@@ -28,9 +28,16 @@ var (
 	errAppNotFound          = errors.DefineNotFound("app_not_found", "app `{app}` not found")
 	errInvalidDevAddr       = errors.DefineInvalidArgument("invalid_dev_addr", "invalid DevAddr `{dev_addr}`")
 	errInvalidDevEUI        = errors.DefineInvalidArgument("invalid_dev_eui", "invalid DevEUI `{dev_eui}`")
+	errInvalidGatewayEUI    = errors.DefineInvalidArgument("invalid_gateway_eui", "invalid gateway EUI `{gateway_eui}`")
 	errInvalidJoinEUI       = errors.DefineInvalidArgument("invalid_join_eui", "invalid JoinEUI `{join_eui}`")
 	errInvalidPHYVersion    = errors.DefineInvalidArgument("invalid_phy_version", "invalid PHY version `{phy_version}`")
 	errInvalidMACVersion    = errors.DefineInvalidArgument("invalid_mac_version", "invalid MAC version `{mac_version}`")
 	errInvalidKey           = errors.DefineInvalidArgument("invalid_key", "invalid key `{key}`")
 	errInvalidApplicationID = errors.DefineInvalidArgument("invalid_application_id", "invalid application ID `{application_id}`")
+
+	errInvalidApplicationName = errors.DefineInvalidArgument("invalid_application_name", "application name `{name}` can not be converted to an application ID")
+	errApplicationIDConflict  = errors.DefineAlreadyExists(
+		"application_id_conflict",
+		"application ID `{application_id}` of ChirpStack application `{chirpstack_application_id}` is already used by ChirpStack application `{other_chirpstack_application_id}`",
+	)
 )
@@ -0,0 +1,134 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"sort"
+	"strings"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/chirpstack/codec"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+// payloadFormatters returns the payload formatters for the codec of a device profile.
+// The variables are passed to the codec functions. It returns nil if the device profile has no codec.
+func (p *Source) payloadFormatters(devProfile *csv4api.DeviceProfile, variables map[string]string) *ttnpb.MessagePayloadFormatters {
+	switch devProfile.PayloadCodecRuntime {
+	case csv4api.CodecRuntime_CAYENNE_LPP:
+		return &ttnpb.MessagePayloadFormatters{
+			UpFormatter:   ttnpb.PayloadFormatter_FORMATTER_CAYENNELPP,
+			DownFormatter: ttnpb.PayloadFormatter_FORMATTER_CAYENNELPP,
+		}
+	case csv4api.CodecRuntime_JS:
+		if devProfile.PayloadCodecScript == "" {
+			return nil
+		}
+		scripts, ok := codec.Translate(devProfile.PayloadCodecScript, variables)
+		if !ok {
+			p.logger.Warnw("Unknown codec script flavor, payload formatters are not exported",
+				"device_profile_id", devProfile.Id,
+			)
+			return nil
+		}
+		formatters := &ttnpb.MessagePayloadFormatters{}
+		if scripts.Uplink != "" {
+			formatters.UpFormatter = ttnpb.PayloadFormatter_FORMATTER_JAVASCRIPT
+			formatters.UpFormatterParameter = scripts.Uplink
+		}
+		if scripts.Downlink != "" {
+			formatters.DownFormatter = ttnpb.PayloadFormatter_FORMATTER_JAVASCRIPT
+			formatters.DownFormatterParameter = scripts.Downlink
+		}
+		return formatters
+	default:
+		return nil
+	}
+}
+
+// getApplicationFormatters returns the default payload formatters of an application.
+// Device profiles with identical codecs are deduplicated, and the formatters that are used by most devices
+// of the application are returned. It returns nil if no device of the application has a codec.
+func (p *Source) getApplicationFormatters(appID string) (*ttnpb.MessagePayloadFormatters, error) {
+	if formatters, ok := p.appFormatters[appID]; ok {
+		return formatters, nil
+	}
+
+	devicesPerProfile := make(map[string]int)
+	client := csv4api.NewDeviceServiceClient(p.ClientConn)
+	offset := uint32(0)
+	for {
+		devices, err := client.List(p.ctx, &csv4api.ListDevicesRequest{
+			ApplicationId: appID,
+			Limit:         limit,
+			Offset:        offset,
+		})
+		if err != nil {
+			return nil, errAPI.WithCause(err)
+		}
+		for _, devListItem := range devices.Result {
+			devicesPerProfile[devListItem.DeviceProfileId]++
+		}
+
+		if offset += limit; offset > devices.TotalCount {
+			break
+		}
+	}
+
+	type candidate struct {
+		formatters *ttnpb.MessagePayloadFormatters
+		devices    int
+	}
+	candidates := make(map[string]*candidate)
+	for profileID, count := range devicesPerProfile {
+		devProfile, err := p.getDeviceProfile(profileID)
+		if err != nil {
+			return nil, err
+		}
+		formatters := p.payloadFormatters(devProfile, nil)
+		if formatters == nil {
+			continue
+		}
+		key := strings.Join([]string{
+			formatters.UpFormatter.String(), formatters.UpFormatterParameter,
+			formatters.DownFormatter.String(), formatters.DownFormatterParameter,
+		}, "\x00")
+		if c, ok := candidates[key]; ok {
+			c.devices += count
+			continue
+		}
+		candidates[key] = &candidate{formatters: formatters, devices: count}
+	}
+
+	keys := make([]string, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+	// Sort the keys so that the same formatters are selected on every run.
+	sort.Strings(keys)
+	var selected *candidate
+	for _, key := range keys {
+		if c := candidates[key]; selected == nil || c.devices > selected.devices {
+			selected = c
+		}
+	}
+
+	var formatters *ttnpb.MessagePayloadFormatters
+	if selected != nil {
+		formatters = selected.formatters
+	}
+	p.appFormatters[appID] = formatters
+	return formatters, nil
+}
@@ -0,0 +1,97 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"math"
+	"strings"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+)
+
+// RangeGateways implements the source.GatewaySource interface.
+func (p *Source) RangeGateways(f func(source.GatewaySource, string) error) error {
+	client := csv4api.NewGatewayServiceClient(p.ClientConn)
+	offset := uint32(0)
+	for {
+		gateways, err := client.List(p.ctx, &csv4api.ListGatewaysRequest{
+			TenantId: p.TenantID,
+			Limit:    limit,
+			Offset:   offset,
+		})
+		if err != nil {
+			return errAPI.WithCause(err)
+		}
+		for _, gtwListItem := range gateways.Result {
+			if err := f(p, gtwListItem.GatewayId); err != nil {
+				return err
+			}
+		}
+
+		if offset += limit; offset > gateways.TotalCount {
+			break
+		}
+	}
+	return nil
+}
+
+// ExportGateway implements the source.GatewaySource interface.
+func (p *Source) ExportGateway(gtwEUI string) (*ttnpb.Gateway, error) {
+	client := csv4api.NewGatewayServiceClient(p.ClientConn)
+	resp, err := client.Get(p.ctx, &csv4api.GetGatewayRequest{
+		GatewayId: gtwEUI,
+	})
+	if err != nil {
+		return nil, errAPI.WithCause(err)
+	}
+	csgtw := resp.Gateway
+
+	gtw := &ttnpb.Gateway{
+		Ids:              &ttnpb.GatewayIdentifiers{},
+		Name:             csgtw.Name,
+		Description:      csgtw.Description,
+		FrequencyPlanId:  p.FrequencyPlanID,
+		FrequencyPlanIds: []string{p.FrequencyPlanID},
+		EnforceDutyCycle: true,
+	}
+	gtw.Ids.Eui, err = util.UnmarshalTextToBytes(&types.EUI64{}, csgtw.GatewayId)
+	if err != nil {
+		return nil, errInvalidGatewayEUI.WithAttributes("gateway_eui", csgtw.GatewayId).WithCause(err)
+	}
+	gtw.Ids.GatewayId = "eui-" + strings.ToLower(csgtw.GatewayId)
+	attributes := util.Attributes{}
+	if csgtw.TenantId != "" {
+		p.setAttributes(&attributes, map[string]string{"chirpstack-tenant-id": csgtw.TenantId}, "gateway_eui", csgtw.GatewayId)
+	}
+	p.setAttributes(&attributes, csgtw.Tags, "gateway_eui", csgtw.GatewayId)
+	gtw.Attributes = attributes.Map()
+
+	if loc := csgtw.Location; loc != nil && (loc.Latitude != 0 || loc.Longitude != 0) {
+		gtw.Antennas = []*ttnpb.GatewayAntenna{{
+			Location: &ttnpb.Location{
+				Latitude:  loc.Latitude,
+				Longitude: loc.Longitude,
+				Altitude:  int32(math.Round(loc.Altitude)),
+				Accuracy:  int32(math.Round(float64(loc.Accuracy))),
+				Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+			},
+		}}
+	}
+	return gtw, nil
+}
@@ -0,0 +1,138 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"encoding/hex"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// keysInvalidatedTag marks ChirpStack devices of which the root keys are invalidated.
+	keysInvalidatedTag = "ttn-lw-migrate-keys-invalidated"
+	// disabledTag marks ChirpStack devices that are disabled after export.
+	disabledTag = "ttn-lw-migrate-disabled"
+)
+
+// isMigrationTag returns true if the device tag is set by the migration tool.
+func isMigrationTag(key string) bool {
+	return key == keysInvalidatedTag || key == disabledTag
+}
+
+// shiftKey adds delta to the last byte of a hex encoded key.
+func shiftKey(key string, delta int) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+	b, err := hex.DecodeString(key)
+	if err != nil {
+		return "", errInvalidKey.WithCause(err)
+	}
+	if len(b) == 0 {
+		return "", errInvalidKey.New()
+	}
+	b[len(b)-1] += byte(delta)
+	return hex.EncodeToString(b), nil
+}
+
+// withShiftedKeys returns a copy of the root keys, with delta added to the last byte of each key.
+func withShiftedKeys(keys *csv4api.DeviceKeys, delta int) (*csv4api.DeviceKeys, error) {
+	ret := proto.Clone(keys).(*csv4api.DeviceKeys)
+	var err error
+	if ret.NwkKey, err = shiftKey(keys.NwkKey, delta); err != nil {
+		return nil, err
+	}
+	if ret.AppKey, err = shiftKey(keys.AppKey, delta); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// originalRootKeys returns the root keys of the device before they were invalidated.
+func originalRootKeys(csdev *csv4api.Device, keys *csv4api.DeviceKeys) (*csv4api.DeviceKeys, error) {
+	if _, ok := csdev.Tags[keysInvalidatedTag]; !ok {
+		return keys, nil
+	}
+	return withShiftedKeys(keys, -1)
+}
+
+// updateSourceDevice invalidates the root keys and disables the ChirpStack device, or reverts
+// these changes on rollback. Devices are tagged, so that changes are only applied and reverted once.
+// keys is nil if the device has no root keys.
+func (p *Source) updateSourceDevice(csdev *csv4api.Device, keys *csv4api.DeviceKeys) error {
+	var (
+		logger     = p.logger.With("dev_eui", csdev.DevEui)
+		updated    = proto.Clone(csdev).(*csv4api.Device)
+		updateKeys *csv4api.DeviceKeys
+		err        error
+	)
+	if updated.Tags == nil {
+		updated.Tags = make(map[string]string)
+	}
+	_, invalidated := csdev.Tags[keysInvalidatedTag]
+	_, disabled := csdev.Tags[disabledTag]
+
+	switch {
+	case p.Rollback:
+		if invalidated {
+			delete(updated.Tags, keysInvalidatedTag)
+			if keys != nil {
+				logger.Info("Restoring device keys")
+				if updateKeys, err = withShiftedKeys(keys, -1); err != nil {
+					return err
+				}
+			}
+		}
+		if disabled {
+			logger.Info("Enabling device")
+			delete(updated.Tags, disabledTag)
+			updated.IsDisabled = false
+		}
+	default:
+		if p.InvalidateKeys && !invalidated && keys != nil {
+			logger.Info("Invalidating device keys")
+			updated.Tags[keysInvalidatedTag] = "true"
+			if updateKeys, err = withShiftedKeys(keys, 1); err != nil {
+				return err
+			}
+		}
+		if p.DisableSourceDevice && !csdev.IsDisabled {
+			logger.Info("Disabling device")
+			updated.Tags[disabledTag] = "true"
+			updated.IsDisabled = true
+		}
+	}
+	if proto.Equal(updated, csdev) {
+		return nil
+	}
+
+	// Update the tags first, so that the keys are never changed without the tag being in sync.
+	client := csv4api.NewDeviceServiceClient(p.ClientConn)
+	if _, err := client.Update(p.ctx, &csv4api.UpdateDeviceRequest{Device: updated}); err != nil {
+		return errAPI.WithCause(err)
+	}
+	if updateKeys == nil {
+		return nil
+	}
+	if _, err := client.UpdateKeys(p.ctx, &csv4api.UpdateDeviceKeysRequest{DeviceKeys: updateKeys}); err != nil {
+		if _, rollbackErr := client.Update(p.ctx, &csv4api.UpdateDeviceRequest{Device: csdev}); rollbackErr != nil {
+			logger.Warnw("Failed to restore device tags", "error", rollbackErr)
+		}
+		return errAPI.WithCause(err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"time"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ADR algorithms of ChirpStack.
+const (
+	adrAlgorithmDefault    = "default"
+	adrAlgorithmLRFHSS     = "lr_fhss"
+	adrAlgorithmLoRaLRFHSS = "lora_lr_fhss"
+)
+
+// setMACSettings sets the MAC settings of the device from the ADR, device status, Rx1 delay and relay
+// settings of the device profile. Settings that can not be represented on The Things Stack are
+// reported once per device profile.
+func (p *Source) setMACSettings(dev *ttnpb.EndDevice, devProfile *csv4api.DeviceProfile) {
+	warn := func(msg string, keysAndValues ...any) {
+		if p.reportedProfiles[devProfile.Id] {
+			return
+		}
+		p.logger.Warnw(msg, append([]any{"device_profile_id", devProfile.Id}, keysAndValues...)...)
+	}
+	defer func() { p.reportedProfiles[devProfile.Id] = true }()
+
+	// ADR
+	// ChirpStack only has ADR algorithms in the device profile. Whether ADR is disabled, the data rate range
+	// and the ADR_ACK_LIMIT and ADR_ACK_DELAY parameters are part of the region configuration, which is not
+	// available through the API. ChirpStack does not change the ADR_ACK parameters of devices, so the
+	// defaults of the LoRaWAN Regional Parameters apply on both networks.
+	dynamic := &ttnpb.ADRSettings{
+		Mode: &ttnpb.ADRSettings_Dynamic{Dynamic: &ttnpb.ADRSettings_DynamicMode{}},
+	}
+	switch id := devProfile.AdrAlgorithmId; id {
+	case "", adrAlgorithmDefault:
+		dev.MacSettings.Adr = dynamic
+	case adrAlgorithmLRFHSS, adrAlgorithmLoRaLRFHSS:
+		warn("LR-FHSS ADR algorithm is not supported, using dynamic ADR", "adr_algorithm_id", id)
+		dev.MacSettings.Adr = dynamic
+	default:
+		warn("Custom ADR algorithm is not supported, using dynamic ADR", "adr_algorithm_id", id)
+		dev.MacSettings.Adr = dynamic
+	}
+	warn("ADR settings of the region configuration are not exported, set disabled or static ADR and the data rate range on the device if needed",
+		"region_config_id", devProfile.RegionConfigId,
+	)
+
+	// Device status
+	// ChirpStack requests the device status a number of times per day.
+	if n := devProfile.DeviceStatusReqInterval; n > 0 {
+		period := 24 * time.Hour / time.Duration(n)
+		dev.MacSettings.StatusTimePeriodicity = durationpb.New(period)
+		if interval := time.Duration(devProfile.UplinkInterval) * time.Second; interval > 0 {
+			count := uint32(period / interval)
+			if count == 0 {
+				count = 1
+			}
+			dev.MacSettings.StatusCountPeriodicity = wrapperspb.UInt32(count)
+		}
+	}
+
+	// Rx1 delay
+	if delay := devProfile.Rx1Delay; delay > 0 && dev.MacSettings.Rx1Delay == nil {
+		dev.MacSettings.DesiredRx1Delay = &ttnpb.RxDelayValue{
+			Value: ttnpb.RxDelay(delay),
+		}
+	}
+
+	// Relay
+	switch {
+	case devProfile.IsRelay:
+		if !devProfile.RelayEnabled {
+			warn("Relay is not enabled, skipping relay settings")
+			break
+		}
+		dev.MacSettings.DesiredRelay = &ttnpb.RelaySettings{
+			Mode: &ttnpb.RelaySettings_Serving{
+				Serving: &ttnpb.ServingRelaySettings{
+					SecondChannel:       relaySecondChannel(devProfile),
+					DefaultChannelIndex: devProfile.RelayDefaultChannelIndex,
+					CadPeriodicity:      ttnpb.RelayCADPeriodicity(devProfile.RelayCadPeriodicity),
+					Limits: &ttnpb.ServingRelayForwardingLimits{
+						JoinRequests: &ttnpb.RelayForwardLimits{
+							BucketSize: ttnpb.RelayLimitBucketSize(devProfile.RelayJoinReqLimitBucketSize),
+							ReloadRate: devProfile.RelayJoinReqLimitReloadRate,
+						},
+						Notifications: &ttnpb.RelayForwardLimits{
+							BucketSize: ttnpb.RelayLimitBucketSize(devProfile.RelayNotifyLimitBucketSize),
+							ReloadRate: devProfile.RelayNotifyLimitReloadRate,
+						},
+						UplinkMessages: &ttnpb.RelayForwardLimits{
+							BucketSize: ttnpb.RelayLimitBucketSize(devProfile.RelayGlobalUplinkLimitBucketSize),
+							ReloadRate: devProfile.RelayGlobalUplinkLimitReloadRate,
+						},
+						Overall: &ttnpb.RelayForwardLimits{
+							BucketSize: ttnpb.RelayLimitBucketSize(devProfile.RelayOverallLimitBucketSize),
+							ReloadRate: devProfile.RelayOverallLimitReloadRate,
+						},
+					},
+				},
+			},
+		}
+		warn("Relay uplink forwarding rules are not exported, add the served devices to the relay")
+	case devProfile.IsRelayEd:
+		served := &ttnpb.ServedRelaySettings{
+			Backoff:       devProfile.RelayEdBackOff,
+			SecondChannel: relaySecondChannel(devProfile),
+		}
+		switch devProfile.RelayEdActivationMode {
+		case csv4api.RelayModeActivation_DISABLE_RELAY_MODE:
+			warn("Relay mode is disabled, skipping relay settings")
+			served = nil
+		case csv4api.RelayModeActivation_ENABLE_RELAY_MODE:
+			served.Mode = &ttnpb.ServedRelaySettings_Always{Always: &ttnpb.RelayEndDeviceAlwaysMode{}}
+		case csv4api.RelayModeActivation_DYNAMIC:
+			served.Mode = &ttnpb.ServedRelaySettings_Dynamic{Dynamic: &ttnpb.RelayEndDeviceDynamicMode{
+				SmartEnableLevel: ttnpb.RelaySmartEnableLevel(devProfile.RelayEdSmartEnableLevel),
+			}}
+		case csv4api.RelayModeActivation_END_DEVICE_CONTROLLED:
+			served.Mode = &ttnpb.ServedRelaySettings_EndDeviceControlled{
+				EndDeviceControlled: &ttnpb.RelayEndDeviceControlledMode{},
+			}
+		}
+		if served != nil {
+			dev.MacSettings.DesiredRelay = &ttnpb.RelaySettings{
+				Mode: &ttnpb.RelaySettings_Served{Served: served},
+			}
+			warn("The serving relay of relayed devices is not exported, set the serving device ID of the device")
+		}
+		if devProfile.RelayEdRelayOnly {
+			warn("Relay only mode is not supported")
+		}
+		if devProfile.RelayEdUplinkLimitBucketSize > 0 || devProfile.RelayEdUplinkLimitReloadRate > 0 {
+			warn("Relay uplink limits of the device are not exported, set them on the uplink forwarding rule of the relay")
+		}
+	}
+
+	// Unsupported settings
+	if devProfile.FlushQueueOnActivate {
+		warn("Flushing the downlink queue on activation is not supported, the queue is kept on join")
+	}
+	if devProfile.AllowRoaming {
+		warn("Roaming is configured on the network, not per device")
+	}
+}
+
+func relaySecondChannel(devProfile *csv4api.DeviceProfile) *ttnpb.RelaySecondChannel {
+	if devProfile.RelaySecondChannelFreq == 0 {
+		return nil
+	}
+	return &ttnpb.RelaySecondChannel{
+		AckOffset:     ttnpb.RelaySecondChAckOffset(devProfile.RelaySecondChannelAckOffset),
+		DataRateIndex: ttnpb.DataRateIndex(devProfile.RelaySecondChannelDr),
+		Frequency:     uint64(devProfile.RelaySecondChannelFreq),
+	}
+}
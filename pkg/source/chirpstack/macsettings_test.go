@@ -0,0 +1,172 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack_test
+
+import (
+	"testing"
+	"time"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+func TestMACSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		profile func(*csv4api.DeviceProfile)
+		assert  func(*assertions.Assertion, *ttnpb.MACSettings)
+	}{
+		{
+			name:    "DefaultADR",
+			profile: func(*csv4api.DeviceProfile) {},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.GetAdr().GetDynamic(), should.NotBeNil)
+				a.So(m.StatusTimePeriodicity, should.BeNil)
+				a.So(m.DesiredRelay, should.BeNil)
+			},
+		},
+		{
+			// Custom ADR algorithms are not supported, so dynamic ADR is used.
+			name: "CustomADR",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.AdrAlgorithmId = "custom"
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.GetAdr().GetDynamic(), should.NotBeNil)
+			},
+		},
+		{
+			// The device status is requested 4 times per day, and the device sends an uplink every hour.
+			name: "DeviceStatus",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.DeviceStatusReqInterval = 4
+				p.UplinkInterval = 3600
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.StatusTimePeriodicity.AsDuration(), should.Equal, 6*time.Hour)
+				a.So(m.StatusCountPeriodicity.GetValue(), should.Equal, uint32(6))
+			},
+		},
+		{
+			name: "DeviceStatusWithoutUplinkInterval",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.DeviceStatusReqInterval = 1
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.StatusTimePeriodicity.AsDuration(), should.Equal, 24*time.Hour)
+				a.So(m.StatusCountPeriodicity, should.BeNil)
+			},
+		},
+		{
+			name: "Rx1Delay",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.Rx1Delay = 5
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.DesiredRx1Delay.GetValue(), should.Equal, ttnpb.RxDelay_RX_DELAY_5)
+			},
+		},
+		{
+			name: "ServingRelay",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.IsRelay = true
+				p.RelayEnabled = true
+				p.RelayDefaultChannelIndex = 1
+				p.RelaySecondChannelFreq = 868100000
+				p.RelaySecondChannelDr = 3
+				p.RelayJoinReqLimitBucketSize = 2
+				p.RelayJoinReqLimitReloadRate = 10
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				serving := m.GetDesiredRelay().GetServing()
+				if !a.So(serving, should.NotBeNil) {
+					return
+				}
+				a.So(serving.DefaultChannelIndex, should.Equal, uint32(1))
+				a.So(serving.SecondChannel.GetFrequency(), should.Equal, uint64(868100000))
+				a.So(serving.SecondChannel.GetDataRateIndex(), should.Equal, ttnpb.DataRateIndex_DATA_RATE_3)
+				a.So(serving.Limits.GetJoinRequests().GetBucketSize(), should.Equal, ttnpb.RelayLimitBucketSize(2))
+				a.So(serving.Limits.GetJoinRequests().GetReloadRate(), should.Equal, uint32(10))
+			},
+		},
+		{
+			name: "DisabledServingRelay",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.IsRelay = true
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.DesiredRelay, should.BeNil)
+			},
+		},
+		{
+			name: "ServedRelayAlways",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.IsRelayEd = true
+				p.RelayEdActivationMode = csv4api.RelayModeActivation_ENABLE_RELAY_MODE
+				p.RelayEdBackOff = 4
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				served := m.GetDesiredRelay().GetServed()
+				if !a.So(served, should.NotBeNil) {
+					return
+				}
+				a.So(served.GetAlways(), should.NotBeNil)
+				a.So(served.Backoff, should.Equal, uint32(4))
+				a.So(served.SecondChannel, should.BeNil)
+			},
+		},
+		{
+			name: "ServedRelayDynamic",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.IsRelayEd = true
+				p.RelayEdActivationMode = csv4api.RelayModeActivation_DYNAMIC
+				p.RelayEdSmartEnableLevel = 2
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				dynamic := m.GetDesiredRelay().GetServed().GetDynamic()
+				if !a.So(dynamic, should.NotBeNil) {
+					return
+				}
+				a.So(dynamic.SmartEnableLevel, should.Equal, ttnpb.RelaySmartEnableLevel(2))
+			},
+		},
+		{
+			name: "ServedRelayDisabled",
+			profile: func(p *csv4api.DeviceProfile) {
+				p.IsRelayEd = true
+				p.RelayEdActivationMode = csv4api.RelayModeActivation_DISABLE_RELAY_MODE
+			},
+			assert: func(a *assertions.Assertion, m *ttnpb.MACSettings) {
+				a.So(m.DesiredRelay, should.BeNil)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			tc.profile(fake.deviceProfiles.profiles[testDeviceProfileID])
+			src := newSource(t, fake, nil)
+
+			dev, err := src.ExportDevice(testDevEUI)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			tc.assert(a, dev.MacSettings)
+		})
+	}
+}
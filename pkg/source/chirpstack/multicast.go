@@ -0,0 +1,208 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+	"go.thethings.network/lorawan-stack/v3/pkg/networkserver/mac"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+)
+
+// multicastGroupReport is a single line of the multicast groups report.
+type multicastGroupReport struct {
+	MulticastGroupID string   `json:"multicast_group_id"`
+	ApplicationID    string   `json:"application_id"`
+	DeviceID         string   `json:"device_id"`
+	DevEUIs          []string `json:"dev_euis"`
+}
+
+// isMulticastGroupID returns whether id is a ChirpStack multicast group ID (UUID) rather than a DevEUI.
+func isMulticastGroupID(id string) bool {
+	return len(id) == 36 && strings.Count(id, "-") == 4
+}
+
+func (p *Source) rangeMulticastGroups(appID string, f func(source.Source, string) error) error {
+	client := csv4api.NewMulticastGroupServiceClient(p.ClientConn)
+	offset := uint32(0)
+	for {
+		groups, err := client.List(p.ctx, &csv4api.ListMulticastGroupsRequest{
+			ApplicationId: appID,
+			Limit:         limit,
+			Offset:        offset,
+		})
+		if err != nil {
+			return errAPI.WithCause(err)
+		}
+		for _, groupListItem := range groups.Result {
+			if err := f(p, groupListItem.Id); err != nil {
+				return err
+			}
+		}
+
+		if offset += limit; offset > groups.TotalCount {
+			break
+		}
+	}
+	return nil
+}
+
+func (p *Source) getMulticastGroupMembers(groupID string) ([]string, error) {
+	client := csv4api.NewDeviceServiceClient(p.ClientConn)
+	var devEUIs []string
+	offset := uint32(0)
+	for {
+		devices, err := client.List(p.ctx, &csv4api.ListDevicesRequest{
+			MulticastGroupId: groupID,
+			Limit:            limit,
+			Offset:           offset,
+		})
+		if err != nil {
+			return nil, errAPI.WithCause(err)
+		}
+		for _, devListItem := range devices.Result {
+			devEUIs = append(devEUIs, devListItem.DevEui)
+		}
+
+		if offset += limit; offset > devices.TotalCount {
+			break
+		}
+	}
+	return devEUIs, nil
+}
+
+// exportMulticastGroup exports a ChirpStack multicast group as a multicast end device.
+func (p *Source) exportMulticastGroup(groupID string) (*ttnpb.EndDevice, error) {
+	client := csv4api.NewMulticastGroupServiceClient(p.ClientConn)
+	resp, err := client.Get(p.ctx, &csv4api.GetMulticastGroupRequest{
+		Id: groupID,
+	})
+	if err != nil {
+		return nil, errAPI.WithCause(err)
+	}
+	group := resp.MulticastGroup
+
+	appID, err := p.applicationID(group.ApplicationId)
+	if err != nil {
+		return nil, err
+	}
+	s := strings.Split(group.Id, "-")
+	dev := &ttnpb.EndDevice{
+		Ids: &ttnpb.EndDeviceIdentifiers{
+			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: appID},
+			DeviceId:       "multicast-" + s[len(s)-1],
+		},
+		Name: group.Name,
+		Attributes: map[string]string{
+			"chirpstack-multicast-group-id": group.Id,
+			"chirpstack-application-id":     group.ApplicationId,
+		},
+		FrequencyPlanId: p.FrequencyPlanID,
+		// ChirpStack does not store the LoRaWAN version of multicast groups.
+		LorawanVersion:    ttnpb.MACVersion_MAC_V1_0_3,
+		LorawanPhyVersion: ttnpb.PHYVersion_RP001_V1_0_3_REV_A,
+		Multicast:         true,
+		MacSettings:       &ttnpb.MACSettings{},
+	}
+
+	switch group.GroupType {
+	case csv4api.MulticastGroupType_CLASS_B:
+		dev.SupportsClassB = true
+		dev.MacSettings.PingSlotDataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(group.Dr)}
+		dev.MacSettings.PingSlotFrequency = &ttnpb.ZeroableFrequencyValue{Value: uint64(group.Frequency)}
+		// ChirpStack stores 2^k ping slots per beacon period, The Things Stack the period between ping slots.
+		if k := group.ClassBPingSlotNbK; k <= 7 {
+			dev.MacSettings.PingSlotPeriodicity = &ttnpb.PingSlotPeriodValue{Value: ttnpb.PingSlotPeriod(7 - k)}
+		}
+	case csv4api.MulticastGroupType_CLASS_C:
+		dev.SupportsClassC = true
+		dev.MacSettings.Rx2DataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(group.Dr)}
+		dev.MacSettings.Rx2Frequency = &ttnpb.FrequencyValue{Value: uint64(group.Frequency)}
+		if group.ClassCSchedulingType == csv4api.MulticastGroupSchedulingType_GPS_TIME {
+			p.logger.Warnw("GPS time scheduling of multicast groups is not supported, downlinks need to be scheduled with an absolute time",
+				"multicast_group_id", group.Id,
+			)
+		}
+	}
+
+	// Session
+	dev.Session = &ttnpb.Session{Keys: &ttnpb.SessionKeys{}}
+	dev.Session.DevAddr, err = util.UnmarshalTextToBytes(&types.DevAddr{}, group.McAddr)
+	if err != nil {
+		return nil, errInvalidDevAddr.WithAttributes("dev_addr", group.McAddr).WithCause(err)
+	}
+	dev.Session.Keys.AppSKey = &ttnpb.KeyEnvelope{}
+	dev.Session.Keys.AppSKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, group.McAppSKey)
+	if err != nil {
+		return nil, errInvalidKey.WithAttributes("key", group.McAppSKey).WithCause(err)
+	}
+	dev.Session.Keys.FNwkSIntKey = &ttnpb.KeyEnvelope{}
+	dev.Session.Keys.FNwkSIntKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, group.McNwkSKey)
+	if err != nil {
+		return nil, errInvalidKey.WithAttributes("key", group.McNwkSKey).WithCause(err)
+	}
+	// ChirpStack stores the next frame counter to use.
+	if group.FCnt > 0 {
+		dev.Session.LastAFCntDown = group.FCnt - 1
+		dev.Session.LastNFCntDown = group.FCnt - 1
+	}
+
+	// Create a MACState.
+	dev.MacState, err = mac.NewState(dev, p.FPStore, &ttnpb.MACSettings{})
+	if err != nil {
+		return nil, err
+	}
+	dev.MacState.CurrentParameters = dev.MacState.DesiredParameters
+
+	// Report member devices.
+	devEUIs, err := p.getMulticastGroupMembers(group.Id)
+	if err != nil {
+		return nil, err
+	}
+	p.logger.Infow("Exported multicast group",
+		"multicast_group_id", group.Id,
+		"device_id", dev.Ids.DeviceId,
+		"dev_euis", devEUIs,
+	)
+	if err := p.reportMulticastGroup(multicastGroupReport{
+		MulticastGroupID: group.Id,
+		ApplicationID:    appID,
+		DeviceID:         dev.Ids.DeviceId,
+		DevEUIs:          devEUIs,
+	}); err != nil {
+		return nil, err
+	}
+	return dev, nil
+}
+
+func (p *Source) reportMulticastGroup(report multicastGroupReport) error {
+	if p.MulticastGroupsReportPath == "" {
+		return nil
+	}
+	if p.multicastReport == nil {
+		f, err := os.Create(p.MulticastGroupsReportPath)
+		if err != nil {
+			return err
+		}
+		p.multicastReport = f
+	}
+	return json.NewEncoder(p.multicastReport).Encode(report)
+}
@@ -0,0 +1,139 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack_test
+
+import (
+	"testing"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+const testMulticastGroupID = "c6b6f1b4-3d1c-4f63-9e65-0a1b2c3d4e5f"
+
+func TestExportMulticastGroup(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		groupType   csv4api.MulticastGroupType
+		pingSlotNbK uint32
+		assert      func(*assertions.Assertion, *ttnpb.EndDevice)
+	}{
+		{
+			// ChirpStack stores 2^k ping slots per beacon period, so one ping slot is every 128 seconds.
+			name:        "ClassB/OnePingSlot",
+			groupType:   csv4api.MulticastGroupType_CLASS_B,
+			pingSlotNbK: 0,
+			assert: func(a *assertions.Assertion, dev *ttnpb.EndDevice) {
+				a.So(dev.SupportsClassB, should.BeTrue)
+				a.So(dev.MacSettings.PingSlotPeriodicity.GetValue(), should.Equal, ttnpb.PingSlotPeriod_PING_EVERY_128S)
+				a.So(dev.MacSettings.PingSlotFrequency.GetValue(), should.Equal, uint64(869525000))
+				a.So(dev.MacSettings.PingSlotDataRateIndex.GetValue(), should.Equal, ttnpb.DataRateIndex_DATA_RATE_3)
+			},
+		},
+		{
+			name:        "ClassB/FourPingSlots",
+			groupType:   csv4api.MulticastGroupType_CLASS_B,
+			pingSlotNbK: 2,
+			assert: func(a *assertions.Assertion, dev *ttnpb.EndDevice) {
+				a.So(dev.MacSettings.PingSlotPeriodicity.GetValue(), should.Equal, ttnpb.PingSlotPeriod_PING_EVERY_32S)
+			},
+		},
+		{
+			name:        "ClassB/EverySecond",
+			groupType:   csv4api.MulticastGroupType_CLASS_B,
+			pingSlotNbK: 7,
+			assert: func(a *assertions.Assertion, dev *ttnpb.EndDevice) {
+				a.So(dev.MacSettings.PingSlotPeriodicity.GetValue(), should.Equal, ttnpb.PingSlotPeriod_PING_EVERY_1S)
+			},
+		},
+		{
+			name:      "ClassC",
+			groupType: csv4api.MulticastGroupType_CLASS_C,
+			assert: func(a *assertions.Assertion, dev *ttnpb.EndDevice) {
+				a.So(dev.SupportsClassB, should.BeFalse)
+				a.So(dev.SupportsClassC, should.BeTrue)
+				a.So(dev.MacSettings.Rx2Frequency.GetValue(), should.Equal, uint64(869525000))
+				a.So(dev.MacSettings.Rx2DataRateIndex.GetValue(), should.Equal, ttnpb.DataRateIndex_DATA_RATE_3)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			fake.multicastGroups.groups[testMulticastGroupID] = &csv4api.MulticastGroup{
+				Id:                testMulticastGroupID,
+				Name:              "group",
+				ApplicationId:     testApplicationID,
+				McAddr:            "01020304",
+				McNwkSKey:         testKey,
+				McAppSKey:         testKey,
+				FCnt:              10,
+				GroupType:         tc.groupType,
+				Dr:                3,
+				Frequency:         869525000,
+				ClassBPingSlotNbK: tc.pingSlotNbK,
+			}
+			fake.devices.members[testMulticastGroupID] = []string{testDevEUI}
+			src := newSource(t, fake, nil)
+
+			dev, err := src.ExportDevice(testMulticastGroupID)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			a.So(dev.Multicast, should.BeTrue)
+			a.So(dev.Ids.DeviceId, should.Equal, "multicast-0a1b2c3d4e5f")
+			a.So(dev.Ids.ApplicationIds.ApplicationId, should.Equal, "chirpstack-4d7bf3c3b6a1")
+			a.So(dev.Session.DevAddr, should.Resemble, []byte{0x01, 0x02, 0x03, 0x04})
+			// ChirpStack stores the next frame counter to use.
+			a.So(dev.Session.LastAFCntDown, should.Equal, uint32(9))
+			tc.assert(a, dev)
+		})
+	}
+}
+
+func TestExportDeviceOrMulticastGroup(t *testing.T) {
+	fake := newFakeChirpStack()
+	fake.multicastGroups.groups[testMulticastGroupID] = &csv4api.MulticastGroup{
+		Id:            testMulticastGroupID,
+		ApplicationId: testApplicationID,
+		McAddr:        "01020304",
+		McNwkSKey:     testKey,
+		McAppSKey:     testKey,
+		GroupType:     csv4api.MulticastGroupType_CLASS_C,
+	}
+	src := newSource(t, fake, nil)
+
+	for _, tc := range []struct {
+		id        string
+		multicast bool
+	}{
+		// DevEUIs are exported as devices.
+		{id: testDevEUI},
+		// UUIDs are exported as multicast groups.
+		{id: testMulticastGroupID, multicast: true},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			a := assertions.New(t)
+			dev, err := src.ExportDevice(tc.id)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			a.So(dev.Multicast, should.Equal, tc.multicast)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack
+
+import (
+	"encoding/hex"
+	"os"
+	"time"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack/v3/pkg/jsonpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+// getDownlinkQueue returns the downlink queue of a ChirpStack device.
+func (p *Source) getDownlinkQueue(devEui string) ([]*csv4api.DeviceQueueItem, error) {
+	client := csv4api.NewDeviceServiceClient(p.ClientConn)
+	resp, err := client.GetQueue(p.ctx, &csv4api.GetDeviceQueueItemsRequest{
+		DevEui: devEui,
+	})
+	if err != nil {
+		return nil, errAPI.WithCause(err)
+	}
+	return resp.Result, nil
+}
+
+// exportDownlinkQueue converts the downlink queue of a ChirpStack device to application downlinks.
+// Pending and expired queue items are skipped.
+//
+// If the session of the device is exported, the frame counters of the downlinks follow the last
+// exported application downlink frame counter. Queue items that are encrypted by the application
+// keep their frame counter, and are skipped if the frame counter is already used. Encrypted queue
+// items are skipped if the session is not exported, since the device joins again.
+func (p *Source) exportDownlinkQueue(dev *ttnpb.EndDevice, items []*csv4api.DeviceQueueItem) []*ttnpb.ApplicationDownlink {
+	var lastFCnt uint32
+	if dev.Session != nil {
+		lastFCnt = dev.Session.LastAFCntDown
+		// LoRaWAN 1.0.x uses a single downlink frame counter.
+		if dev.LorawanVersion.Compare(ttnpb.MACVersion_MAC_V1_1) < 0 && dev.Session.LastNFCntDown > lastFCnt {
+			lastFCnt = dev.Session.LastNFCntDown
+		}
+	}
+
+	downlinks := make([]*ttnpb.ApplicationDownlink, 0, len(items))
+	for _, item := range items {
+		logger := p.logger.With("dev_eui", item.DevEui, "queue_item_id", item.Id)
+		switch {
+		case item.IsPending:
+			logger.Warn("Skip pending downlink, it is already sent to the device")
+			continue
+		case item.ExpiresAt != nil && item.ExpiresAt.AsTime().Before(time.Now()):
+			logger.Debug("Skip expired downlink")
+			continue
+		}
+
+		down := &ttnpb.ApplicationDownlink{
+			FPort:          item.FPort,
+			FrmPayload:     item.Data,
+			DecodedPayload: item.Object,
+			Confirmed:      item.Confirmed,
+			Priority:       ttnpb.TxSchedulePriority_NORMAL,
+			CorrelationIds: []string{"chirpstack:queue_item:" + item.Id},
+		}
+		switch {
+		case item.IsEncrypted && dev.Session == nil:
+			logger.Warn("Skip encrypted downlink, the session is not exported")
+			continue
+		case item.IsEncrypted && item.FCntDown <= lastFCnt:
+			logger.Warnw("Skip encrypted downlink, the frame counter is already used",
+				"f_cnt", item.FCntDown,
+				"last_f_cnt_down", lastFCnt,
+			)
+			continue
+		case item.IsEncrypted:
+			lastFCnt = item.FCntDown
+		case dev.Session != nil:
+			lastFCnt++
+		}
+		if dev.Session != nil {
+			down.FCnt = lastFCnt
+			down.SessionKeyId = dev.Session.Keys.SessionKeyId
+		}
+		downlinks = append(downlinks, down)
+	}
+	return downlinks
+}
+
+// writeDownlinkQueue writes the downlinks of the device to the downlink queue file.
+// Each line is a request to push the downlinks to the Application Server.
+func (p *Source) writeDownlinkQueue(ids *ttnpb.EndDeviceIdentifiers, downlinks []*ttnpb.ApplicationDownlink) error {
+	if p.downlinkQueueFile == nil {
+		f, err := os.Create(p.DownlinkQueuePath)
+		if err != nil {
+			return err
+		}
+		p.downlinkQueueFile = f
+	}
+	b, err := jsonpb.TTN().Marshal(&ttnpb.DownlinkQueueRequest{
+		EndDeviceIds: ids,
+		Downlinks:    downlinks,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = p.downlinkQueueFile.Write(append(b, '\n'))
+	return err
+}
+
+// queueKey returns the key of the downlink queue and the source device of the device.
+func queueKey(ids *ttnpb.EndDeviceIdentifiers) string {
+	return hex.EncodeToString(ids.DevEui)
+}
+
+// DeviceExported implements the source.ExportedDeviceHandler interface.
+// The downlink queue of the device is written with the identifiers of the exported device,
+// and the ChirpStack device is disabled, invalidated or restored.
+func (p *Source) DeviceExported(dev *ttnpb.EndDevice) error {
+	key := queueKey(dev.Ids)
+	if downlinks, ok := p.downlinkQueues[key]; ok {
+		delete(p.downlinkQueues, key)
+		if err := p.writeDownlinkQueue(dev.Ids, downlinks); err != nil {
+			return err
+		}
+		p.logger.Infow("Exported downlink queue",
+			"dev_eui", key,
+			"device_id", dev.Ids.DeviceId,
+			"count", len(downlinks),
+		)
+	}
+	if csdev, ok := p.sourceDevices[key]; ok {
+		delete(p.sourceDevices, key)
+		return p.updateSourceDevice(csdev.dev, csdev.keys)
+	}
+	return nil
+}
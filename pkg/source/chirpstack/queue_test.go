@@ -0,0 +1,119 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/export"
+	"go.thethings.network/lorawan-stack/v3/pkg/jsonpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+func TestExportDownlinkQueue(t *testing.T) {
+	items := []*csv4api.DeviceQueueItem{
+		{Id: "plain-1", FPort: 1, Data: []byte{0x01}},
+		{Id: "encrypted-used", FPort: 1, Data: []byte{0x02}, IsEncrypted: true, FCntDown: 4},
+		{Id: "encrypted", FPort: 1, Data: []byte{0x03}, IsEncrypted: true, FCntDown: 10},
+		{Id: "plain-2", FPort: 2, Data: []byte{0x04}, Confirmed: true},
+		{Id: "pending", FPort: 1, Data: []byte{0x05}, IsPending: true},
+		{Id: "expired", FPort: 1, Data: []byte{0x06}, ExpiresAt: timestamppb.New(time.Now().Add(-time.Hour))},
+	}
+	for _, tc := range []struct {
+		name string
+		abp  bool
+		// ids contains the queue item IDs of the exported downlinks.
+		ids   []string
+		fCnts []uint32
+	}{
+		{
+			// The frame counters follow the last downlink frame counter of the session. Encrypted downlinks
+			// keep their frame counter, and are skipped if the frame counter is already used.
+			name:  "Session",
+			abp:   true,
+			ids:   []string{"plain-1", "encrypted", "plain-2"},
+			fCnts: []uint32{6, 10, 11},
+		},
+		{
+			// Encrypted downlinks are skipped, since the device joins again.
+			name:  "NoSession",
+			ids:   []string{"plain-1", "plain-2"},
+			fCnts: []uint32{0, 0},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			fake.devices.queues[testDevEUI] = items
+			if tc.abp {
+				fake.deviceProfiles.profiles[testDeviceProfileID].SupportsOtaa = false
+				fake.devices.activations[testDevEUI] = &csv4api.DeviceActivation{
+					DevEui:      testDevEUI,
+					DevAddr:     "01020304",
+					AppSKey:     testKey,
+					FNwkSIntKey: testKey,
+					FCntUp:      20,
+					// LoRaWAN 1.0.x uses a single downlink frame counter.
+					NFCntDown: 5,
+				}
+			}
+			path := filepath.Join(t.TempDir(), "queue.json")
+			src := newSource(t, fake, map[string]string{"downlink-queue-file": path})
+
+			if err := (export.Config{}).ExportDev(src, testDevEUI); !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			var reqs []*ttnpb.DownlinkQueueRequest
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				req := &ttnpb.DownlinkQueueRequest{}
+				if err := jsonpb.TTN().Unmarshal(scanner.Bytes(), req); err != nil {
+					t.Fatal(err)
+				}
+				reqs = append(reqs, req)
+			}
+			if !a.So(reqs, should.HaveLength, 1) {
+				t.FailNow()
+			}
+			a.So(reqs[0].EndDeviceIds.DeviceId, should.Equal, "eui-"+testDevEUI)
+			var (
+				ids   []string
+				fCnts []uint32
+			)
+			for _, down := range reqs[0].Downlinks {
+				ids = append(ids, strings.TrimPrefix(down.CorrelationIds[0], "chirpstack:queue_item:"))
+				fCnts = append(fCnts, down.FCnt)
+			}
+			a.So(ids, should.Resemble, tc.ids)
+			a.So(fCnts, should.Resemble, tc.fCnts)
+		})
+	}
+}
@@ -16,9 +16,10 @@ package chirpstack
 
 import (
 	"context"
-	"fmt"
+	"maps"
 	"math"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -31,31 +32,42 @@ import (
 	"go.thethings.network/lorawan-stack/v3/pkg/networkserver/mac"
 	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
 	"go.thethings.network/lorawan-stack/v3/pkg/types"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
-)
-
-const (
-	encoderFormat = `%s
-
-function Encoder(payload, fport) {
-	return Encode(fport, payload, null);
-}`
-	decoderFormat = `%s
-
-function Decoder(bytes, fport) {
-	return Decode(fport, bytes, null);
-}`
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 // Source implements the Source interface.
 type Source struct {
 	*config.Config
 
-	ctx context.Context
+	ctx    context.Context
+	logger *zap.SugaredLogger
+
+	applications  map[string]*csv4api.Application
+	devProfiles   map[string]*csv4api.DeviceProfile
+	appFormatters map[string]*ttnpb.MessagePayloadFormatters
+	// appIDs maps exported application IDs to ChirpStack application IDs.
+	appIDs map[string]string
+	// reportedProfiles contains the IDs of device profiles of which unsupported settings are reported.
+	reportedProfiles map[string]bool
+	// missingJoinEUIs contains the DevEUIs of OTAA devices that are exported without JoinEUI.
+	missingJoinEUIs []string
+
+	multicastReport   *os.File
+	downlinkQueueFile *os.File
+	// downlinkQueues contains the downlinks of devices that are not exported yet.
+	downlinkQueues map[string][]*ttnpb.ApplicationDownlink
+	// sourceDevices contains the ChirpStack devices that are updated when they are exported.
+	sourceDevices map[string]sourceDevice
+}
 
-	applications map[string]*csv4api.Application
-	devProfiles  map[string]*csv4api.DeviceProfile
+// sourceDevice is a ChirpStack device with its root keys. keys is nil if the device has no root keys.
+type sourceDevice struct {
+	dev  *csv4api.Device
+	keys *csv4api.DeviceKeys
 }
 
 func createNewSource(cfg *config.Config) source.CreateSource {
@@ -64,16 +76,29 @@ func createNewSource(cfg *config.Config) source.CreateSource {
 			return nil, err
 		}
 		return &Source{
-			ctx:          ctx,
-			Config:       cfg,
-			applications: make(map[string]*csv4api.Application),
-			devProfiles:  make(map[string]*csv4api.DeviceProfile),
+			ctx:              ctx,
+			logger:           src.Logger,
+			Config:           cfg,
+			applications:     make(map[string]*csv4api.Application),
+			devProfiles:      make(map[string]*csv4api.DeviceProfile),
+			appFormatters:    make(map[string]*ttnpb.MessagePayloadFormatters),
+			appIDs:           make(map[string]string),
+			reportedProfiles: make(map[string]bool),
+			downlinkQueues:   make(map[string][]*ttnpb.ApplicationDownlink),
+			sourceDevices:    make(map[string]sourceDevice),
 		}, nil
 	}
 }
 
 // Iterator implements source.Source.
-func (s Source) Iterator(bool) iterator.Iterator {
+func (s Source) Iterator(isApplication bool) iterator.Iterator {
+	if isApplication && s.AllApplications {
+		return &applicationIterator{
+			ctx:      s.ctx,
+			client:   csv4api.NewApplicationServiceClient(s.ClientConn),
+			tenantID: s.TenantID,
+		}
+	}
 	return iterator.NewReaderIterator(os.Stdin, '\n')
 }
 
@@ -104,23 +129,30 @@ func (p *Source) RangeDevices(id string, f func(source.Source, string) error) er
 			break
 		}
 	}
+	if p.ExportMulticastGroups {
+		return p.rangeMulticastGroups(app.Id, f)
+	}
 	return nil
 }
 
 // ExportDevice implements the Source interface.
 func (p *Source) ExportDevice(devEui string) (*ttnpb.EndDevice, error) {
+	if isMulticastGroupID(devEui) {
+		return p.exportMulticastGroup(devEui)
+	}
+
 	// Allocate
 	dev := &ttnpb.EndDevice{}
-	dev.Attributes = make(map[string]string)
 	dev.Formatters = &ttnpb.MessagePayloadFormatters{}
 	dev.Ids = &ttnpb.EndDeviceIdentifiers{ApplicationIds: &ttnpb.ApplicationIdentifiers{}}
 	dev.MacSettings = &ttnpb.MACSettings{}
 	dev.RootKeys = &ttnpb.RootKeys{}
 
-	csdev, err := p.getDevice(devEui)
+	resp, err := p.getDevice(devEui)
 	if err != nil {
 		return nil, err
 	}
+	csdev := resp.Device
 	devProfile, err := p.getDeviceProfile(csdev.DeviceProfileId)
 	if err != nil {
 		return nil, err
@@ -131,34 +163,58 @@ func (p *Source) ExportDevice(devEui string) (*ttnpb.EndDevice, error) {
 	if err != nil {
 		return nil, errInvalidDevEUI.WithAttributes("dev_eui", devEui).WithCause(err)
 	}
-	dev.Ids.JoinEui = p.JoinEUI.Bytes()
-	// Get last index of the application ID (UUID).
-	s := strings.Split(csdev.ApplicationId, "-")
-	if len(s) < 2 {
-		return nil, errInvalidApplicationID.WithAttributes("application_id", csdev.ApplicationId)
+	dev.Ids.JoinEui, err = p.joinEUI(csdev, devProfile)
+	if err != nil {
+		return nil, err
+	}
+	dev.Ids.ApplicationIds.ApplicationId, err = p.applicationID(csdev.ApplicationId)
+	if err != nil {
+		return nil, err
 	}
-	dev.Ids.ApplicationIds.ApplicationId = fmt.Sprintf("chirpstack-%s", s[len(s)-1])
 	dev.Ids.DeviceId = "eui-" + strings.ToLower(devEui)
 
 	// Information
 	dev.Name = csdev.Name
 	dev.Description = csdev.Description
-	dev.Attributes["chirpstack-device-profile"] = csdev.DeviceProfileId
-	dev.Attributes["chirpstack-application-id"] = csdev.ApplicationId
-	for key, value := range devProfile.Tags {
-		dev.Attributes[key] = value
-	}
+	attributes := util.Attributes{}
+	p.setAttributes(&attributes, map[string]string{
+		"chirpstack-device-profile": csdev.DeviceProfileId,
+		"chirpstack-application-id": csdev.ApplicationId,
+	}, "dev_eui", devEui)
+	p.setAttributes(&attributes, devProfile.Tags, "dev_eui", devEui, "device_profile_id", devProfile.Id)
+	tags := make(map[string]string, len(csdev.Tags))
 	for key, value := range csdev.Tags {
-		dev.Attributes[key] = value
+		if !isMigrationTag(key) {
+			tags[key] = value
+		}
 	}
+	p.setAttributes(&attributes, tags, "dev_eui", devEui)
 	if p.ExportVars {
+		vars := make(map[string]string, len(csdev.Variables))
 		for key, value := range csdev.Variables {
-			dev.Attributes["var-"+key] = value
+			vars["var-"+key] = value
+		}
+		p.setAttributes(&attributes, vars, "dev_eui", devEui)
+	}
+	dev.Attributes = attributes.Map()
+	if location := deviceLocation(csdev); location != nil {
+		dev.Locations = map[string]*ttnpb.Location{
+			"user": location,
 		}
 	}
 
-	if d := devProfile.DeviceStatusReqInterval; d > 0 {
-		dev.MacSettings.StatusTimePeriodicity = durationpb.New(time.Duration(d))
+	// Status
+	dev.LastSeenAt = resp.LastSeenAt
+	if status := resp.DeviceStatus; status != nil {
+		dev.DownlinkMargin = status.Margin
+		switch {
+		case status.ExternalPowerSource:
+			dev.PowerState = ttnpb.PowerState_POWER_EXTERNAL
+		case status.BatteryLevel >= 0:
+			dev.PowerState = ttnpb.PowerState_POWER_BATTERY
+			dev.BatteryPercentage = wrapperspb.Float(status.BatteryLevel / 100)
+		}
+		// ChirpStack does not store when the device status was received, so the time is not set.
 	}
 
 	// Frequency Plan
@@ -228,6 +284,9 @@ func (p *Source) ExportDevice(devEui string) (*ttnpb.EndDevice, error) {
 		}
 	}
 
+	// MAC settings
+	p.setMACSettings(dev, devProfile)
+
 	// Class B
 	dev.SupportsClassB = devProfile.SupportsClassB
 	if dev.SupportsClassB {
@@ -257,8 +316,12 @@ func (p *Source) ExportDevice(devEui string) (*ttnpb.EndDevice, error) {
 	}
 
 	// Root Keys
-	rootKeys, err := p.getRootKeys(devEui)
+	csRootKeys, err := p.getRootKeys(devEui)
 	if err == nil {
+		rootKeys, err := originalRootKeys(csdev, csRootKeys)
+		if err != nil {
+			return nil, err
+		}
 		switch dev.LorawanVersion {
 		case ttnpb.MACVersion_MAC_V1_1:
 			dev.RootKeys.AppKey = &ttnpb.KeyEnvelope{}
@@ -282,16 +345,17 @@ func (p *Source) ExportDevice(devEui string) (*ttnpb.EndDevice, error) {
 	}
 
 	// Payload formatters
-	switch devProfile.PayloadCodecRuntime {
-	case csv4api.CodecRuntime_CAYENNE_LPP:
-		dev.Formatters.UpFormatter = ttnpb.PayloadFormatter_FORMATTER_CAYENNELPP
-		dev.Formatters.DownFormatter = ttnpb.PayloadFormatter_FORMATTER_CAYENNELPP
-	case csv4api.CodecRuntime_JS:
-		if s := devProfile.PayloadCodecScript; s != "" {
-			dev.Formatters.UpFormatter = ttnpb.PayloadFormatter_FORMATTER_JAVASCRIPT
-			dev.Formatters.DownFormatter = ttnpb.PayloadFormatter_FORMATTER_JAVASCRIPT
-			dev.Formatters.UpFormatterParameter = fmt.Sprintf(encoderFormat, s)
-			dev.Formatters.DownFormatterParameter = fmt.Sprintf(encoderFormat, s)
+	if formatters := p.payloadFormatters(devProfile, csdev.Variables); formatters != nil {
+		dev.Formatters = formatters
+	}
+	if p.FormattersOnApplication && len(csdev.Variables) == 0 {
+		appFormatters, err := p.getApplicationFormatters(csdev.ApplicationId)
+		if err != nil {
+			return nil, err
+		}
+		if appFormatters != nil && proto.Equal(appFormatters, dev.Formatters) {
+			// Use the default formatters of the application.
+			dev.Formatters = nil
 		}
 	}
 
@@ -361,10 +425,56 @@ func (p *Source) ExportDevice(devEui string) (*ttnpb.EndDevice, error) {
 		dev.MacState.CurrentParameters.Rx1Delay = dev.MacSettings.Rx1Delay.Value
 	}
 
+	// Downlink queue
+	if p.DownlinkQueuePath != "" {
+		items, err := p.getDownlinkQueue(devEui)
+		if err != nil {
+			return nil, err
+		}
+		if downlinks := p.exportDownlinkQueue(dev, items); len(downlinks) > 0 {
+			// The downlinks are written when the device is exported, with the final device ID.
+			p.downlinkQueues[queueKey(dev.Ids)] = downlinks
+		}
+	}
+
+	if p.DisableSourceDevice || p.InvalidateKeys || p.Rollback {
+		// The ChirpStack device is updated when the device is exported, so that devices that fail
+		// validation are not changed.
+		p.sourceDevices[queueKey(dev.Ids)] = sourceDevice{dev: csdev, keys: csRootKeys}
+	}
+
 	return dev, nil
 }
 
 // Close implements the Source interface.
 func (p *Source) Close() error {
+	if len(p.missingJoinEUIs) > 0 {
+		p.logger.Warnw("Exported OTAA devices without JoinEUI, set the JoinEUI before importing",
+			"count", len(p.missingJoinEUIs),
+			"dev_euis", p.missingJoinEUIs,
+		)
+	}
+	if p.multicastReport != nil {
+		if err := p.multicastReport.Close(); err != nil {
+			return err
+		}
+	}
+	if p.downlinkQueueFile != nil {
+		if err := p.downlinkQueueFile.Close(); err != nil {
+			return err
+		}
+	}
 	return p.ClientConn.Close()
 }
+
+// setAttributes sets the tags as attributes, in order of the keys. Tags that are not valid attributes on
+// The Things Stack are skipped with a warning.
+func (p *Source) setAttributes(attributes *util.Attributes, tags map[string]string, keysAndValues ...any) {
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		if err := attributes.Set(key, tags[key]); err != nil {
+			p.logger.Warnw("Skip tag that is not a valid attribute",
+				append([]any{"key", key, "error", err}, keysAndValues...)...,
+			)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"github.com/chirpstack/chirpstack/api/go/v4/common"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/export"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	_ "go.thethings.network/lorawan-stack-migrate/pkg/source/chirpstack"
+)
+
+const (
+	testDevEUI          = "0102030405060708"
+	testApplicationID   = "f5b5ba6a-3b38-4a4d-9d52-4d7bf3c3b6a1"
+	testDeviceProfileID = "7d5ee9a7-0ec3-4b6c-a1d4-f6b1ab3a8e2c"
+	testKey             = "01020304050607080102030405060708"
+)
+
+// testDevice returns an OTAA device of the test application with the test device profile.
+func testDevice() *csv4api.Device {
+	return &csv4api.Device{
+		DevEui:          testDevEUI,
+		Name:            "sensor",
+		ApplicationId:   testApplicationID,
+		DeviceProfileId: testDeviceProfileID,
+		JoinEui:         "0807060504030201",
+	}
+}
+
+// testDeviceProfile returns a LoRaWAN 1.0.3 OTAA device profile with the test device profile ID.
+func testDeviceProfile() *csv4api.DeviceProfile {
+	return &csv4api.DeviceProfile{
+		Id:           testDeviceProfileID,
+		MacVersion:   common.MacVersion_LORAWAN_1_0_3,
+		SupportsOtaa: true,
+	}
+}
+
+type deviceServer struct {
+	csv4api.UnimplementedDeviceServiceServer
+
+	mu          sync.Mutex
+	devices     map[string]*csv4api.GetDeviceResponse
+	keys        map[string]*csv4api.DeviceKeys
+	activations map[string]*csv4api.DeviceActivation
+	queues      map[string][]*csv4api.DeviceQueueItem
+	// members contains the DevEUIs of the devices by multicast group ID.
+	members     map[string][]string
+	updates     []*csv4api.Device
+	updatedKeys []*csv4api.DeviceKeys
+}
+
+func (s *deviceServer) Get(_ context.Context, req *csv4api.GetDeviceRequest) (*csv4api.GetDeviceResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, ok := s.devices[req.DevEui]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "device not found")
+	}
+	return resp, nil
+}
+
+func (s *deviceServer) GetKeys(_ context.Context, req *csv4api.GetDeviceKeysRequest) (*csv4api.GetDeviceKeysResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys, ok := s.keys[req.DevEui]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "device keys not found")
+	}
+	return &csv4api.GetDeviceKeysResponse{DeviceKeys: keys}, nil
+}
+
+func (s *deviceServer) GetActivation(_ context.Context, req *csv4api.GetDeviceActivationRequest) (*csv4api.GetDeviceActivationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	activation, ok := s.activations[req.DevEui]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "device activation not found")
+	}
+	return &csv4api.GetDeviceActivationResponse{DeviceActivation: activation}, nil
+}
+
+func (s *deviceServer) GetQueue(_ context.Context, req *csv4api.GetDeviceQueueItemsRequest) (*csv4api.GetDeviceQueueItemsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := s.queues[req.DevEui]
+	return &csv4api.GetDeviceQueueItemsResponse{Result: items, TotalCount: uint32(len(items))}, nil
+}
+
+func (s *deviceServer) List(_ context.Context, req *csv4api.ListDevicesRequest) (*csv4api.ListDevicesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := &csv4api.ListDevicesResponse{}
+	for _, devEUI := range s.members[req.MulticastGroupId] {
+		resp.Result = append(resp.Result, &csv4api.DeviceListItem{DevEui: devEUI})
+	}
+	resp.TotalCount = uint32(len(resp.Result))
+	return resp, nil
+}
+
+func (s *deviceServer) Update(_ context.Context, req *csv4api.UpdateDeviceRequest) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates = append(s.updates, req.Device)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *deviceServer) UpdateKeys(_ context.Context, req *csv4api.UpdateDeviceKeysRequest) (*emptypb.Empty, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updatedKeys = append(s.updatedKeys, req.DeviceKeys)
+	return &emptypb.Empty{}, nil
+}
+
+type deviceProfileServer struct {
+	csv4api.UnimplementedDeviceProfileServiceServer
+
+	profiles map[string]*csv4api.DeviceProfile
+}
+
+func (s *deviceProfileServer) Get(_ context.Context, req *csv4api.GetDeviceProfileRequest) (*csv4api.GetDeviceProfileResponse, error) {
+	profile, ok := s.profiles[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "device profile not found")
+	}
+	return &csv4api.GetDeviceProfileResponse{DeviceProfile: profile}, nil
+}
+
+type multicastGroupServer struct {
+	csv4api.UnimplementedMulticastGroupServiceServer
+
+	groups map[string]*csv4api.MulticastGroup
+}
+
+func (s *multicastGroupServer) Get(_ context.Context, req *csv4api.GetMulticastGroupRequest) (*csv4api.GetMulticastGroupResponse, error) {
+	group, ok := s.groups[req.Id]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "multicast group not found")
+	}
+	return &csv4api.GetMulticastGroupResponse{MulticastGroup: group}, nil
+}
+
+// fakeChirpStack is a fake ChirpStack API. The test device and device profile are added by default.
+type fakeChirpStack struct {
+	devices         *deviceServer
+	deviceProfiles  *deviceProfileServer
+	multicastGroups *multicastGroupServer
+}
+
+func newFakeChirpStack() *fakeChirpStack {
+	return &fakeChirpStack{
+		devices: &deviceServer{
+			devices: map[string]*csv4api.GetDeviceResponse{
+				testDevEUI: {Device: testDevice()},
+			},
+			keys:        make(map[string]*csv4api.DeviceKeys),
+			activations: make(map[string]*csv4api.DeviceActivation),
+			queues:      make(map[string][]*csv4api.DeviceQueueItem),
+			members:     make(map[string][]string),
+		},
+		deviceProfiles: &deviceProfileServer{
+			profiles: map[string]*csv4api.DeviceProfile{
+				testDeviceProfileID: testDeviceProfile(),
+			},
+		},
+		multicastGroups: &multicastGroupServer{
+			groups: make(map[string]*csv4api.MulticastGroup),
+		},
+	}
+}
+
+// newSource serves the fake ChirpStack API, and returns a ChirpStack source that uses it, with the flags set.
+func newSource(t *testing.T, fake *fakeChirpStack, flagValues map[string]string) source.Source {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	csv4api.RegisterDeviceServiceServer(server, fake.devices)
+	csv4api.RegisterDeviceProfileServiceServer(server, fake.deviceProfiles)
+	csv4api.RegisterMulticastGroupServiceServer(server, fake.multicastGroups)
+	go server.Serve(lis) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	flags, err := source.FlagSet("chirpstack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{
+		"api-url":               lis.Addr().String(),
+		"api-key":               "test",
+		"insecure":              "true",
+		"frequency-plan-id":     "EU_863_870",
+		"join-eui":              "",
+		"export-vars":           "false",
+		"export-session":        "false",
+		"downlink-queue-file":   "",
+		"invalidate-keys":       "false",
+		"disable-source-device": "false",
+		"rollback":              "false",
+	}
+	for name, value := range flagValues {
+		values[name] = value
+	}
+	for name, value := range values {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source.RootConfig.SetSource("chirpstack")
+	source.RootConfig.FrequencyPlansURL = "https://raw.githubusercontent.com/TheThingsNetwork/lorawan-frequency-plans/master"
+	src, err := source.NewSource(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+	t.Cleanup(func() { src.Close() })
+	return src
+}
+
+func TestUpdateSourceDevice(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		devName string
+		valid   bool
+	}{
+		{
+			// The name is too long for The Things Stack, so the device fails validation.
+			name:    "Invalid",
+			devName: strings.Repeat("a", 51),
+		},
+		{
+			name:    "Valid",
+			devName: "sensor",
+			valid:   true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			fake.devices.devices[testDevEUI].Device.Name = tc.devName
+			fake.devices.keys[testDevEUI] = &csv4api.DeviceKeys{DevEui: testDevEUI, NwkKey: testKey}
+			src := newSource(t, fake, map[string]string{
+				"invalidate-keys":       "true",
+				"disable-source-device": "true",
+			})
+
+			err := export.Config{}.ExportDev(src, testDevEUI)
+
+			fake.devices.mu.Lock()
+			defer fake.devices.mu.Unlock()
+			if !tc.valid {
+				a.So(err, should.NotBeNil)
+				a.So(fake.devices.updates, should.BeEmpty)
+				a.So(fake.devices.updatedKeys, should.BeEmpty)
+				return
+			}
+			a.So(err, should.BeNil)
+			if a.So(fake.devices.updates, should.HaveLength, 1) {
+				a.So(fake.devices.updates[0].IsDisabled, should.BeTrue)
+				a.So(fake.devices.updates[0].Tags, should.ContainKey, "ttn-lw-migrate-keys-invalidated")
+			}
+			if a.So(fake.devices.updatedKeys, should.HaveLength, 1) {
+				a.So(fake.devices.updatedKeys[0].NwkKey, should.Equal, "01020304050607080102030405060709")
+			}
+		})
+	}
+}
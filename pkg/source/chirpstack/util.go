@@ -16,10 +16,19 @@ package chirpstack
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
 
 	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/chirpstack/config"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 	log "go.thethings.network/lorawan-stack/v3/pkg/log"
 	"go.thethings.network/lorawan-stack/v3/pkg/random"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -30,6 +39,78 @@ func generateBytes(length int) []byte {
 	return random.Bytes(length)
 }
 
+// applicationID returns the application ID for a ChirpStack application ID (UUID).
+// Applications in the mapping file are mapped by ID or name. Otherwise, the application ID
+// is derived from the application name or from the last index of the UUID, depending on the
+// configured format.
+func (p *Source) applicationID(csAppID string) (string, error) {
+	appID, mapped, err := p.mapApplicationID(csAppID)
+	if err != nil {
+		return "", err
+	}
+	// Applications that are mapped explicitly may be merged on purpose.
+	if other, ok := p.appIDs[appID]; ok && other != csAppID && !mapped {
+		return "", errApplicationIDConflict.WithAttributes(
+			"application_id", appID,
+			"chirpstack_application_id", csAppID,
+			"other_chirpstack_application_id", other,
+		)
+	}
+	p.appIDs[appID] = csAppID
+	return appID, nil
+}
+
+// mapApplicationID returns the application ID for a ChirpStack application ID (UUID),
+// and whether the application is mapped explicitly in the mapping file.
+func (p *Source) mapApplicationID(csAppID string) (string, bool, error) {
+	if appID, ok := p.ApplicationIDs[csAppID]; ok {
+		return appID, true, nil
+	}
+	if len(p.ApplicationIDs) > 0 || p.ApplicationIDFormat == config.ApplicationIDFormatNameSlug {
+		csapp, err := p.getApplicationByID(csAppID)
+		if err != nil {
+			return "", false, err
+		}
+		if appID, ok := p.ApplicationIDs[csapp.Name]; ok {
+			return appID, true, nil
+		}
+		if p.ApplicationIDFormat == config.ApplicationIDFormatNameSlug {
+			appID := util.Slug(csapp.Name)
+			if err := util.ValidateID(appID); err != nil {
+				return "", false, errInvalidApplicationName.WithAttributes("name", csapp.Name).WithCause(err)
+			}
+			return appID, false, nil
+		}
+	}
+	s := strings.Split(csAppID, "-")
+	if len(s) < 2 {
+		return "", false, errInvalidApplicationID.WithAttributes("application_id", csAppID)
+	}
+	return fmt.Sprintf("chirpstack-%s", s[len(s)-1]), false, nil
+}
+
+// joinEUI returns the JoinEUI of a ChirpStack device. If the device has no JoinEUI,
+// the configured JoinEUI is used. OTAA devices without JoinEUI are reported.
+func (p *Source) joinEUI(csdev *csv4api.Device, devProfile *csv4api.DeviceProfile) ([]byte, error) {
+	if csdev.JoinEui != "" {
+		joinEUI := &types.EUI64{}
+		if err := joinEUI.UnmarshalText([]byte(csdev.JoinEui)); err != nil {
+			return nil, errInvalidJoinEUI.WithAttributes("join_eui", csdev.JoinEui).WithCause(err)
+		}
+		if !joinEUI.IsZero() {
+			return joinEUI.Bytes(), nil
+		}
+	}
+	if p.JoinEUI != nil {
+		return p.JoinEUI.Bytes(), nil
+	}
+	if devProfile.SupportsOtaa {
+		p.logger.Warnw("No JoinEUI for device, set --join-eui to use a fallback", "dev_eui", csdev.DevEui)
+		p.missingJoinEUIs = append(p.missingJoinEUIs, csdev.DevEui)
+	}
+	return nil, nil
+}
+
 func (p *Source) getDeviceProfile(id string) (*csv4api.DeviceProfile, error) {
 	if profile, ok := p.devProfiles[id]; ok {
 		return profile, nil
@@ -46,7 +127,7 @@ func (p *Source) getDeviceProfile(id string) (*csv4api.DeviceProfile, error) {
 	return resp.DeviceProfile, nil
 }
 
-func (p *Source) getDevice(devEui string) (*csv4api.Device, error) {
+func (p *Source) getDevice(devEui string) (*csv4api.GetDeviceResponse, error) {
 	client := csv4api.NewDeviceServiceClient(p.ClientConn)
 
 	resp, err := client.Get(p.ctx, &csv4api.GetDeviceRequest{
@@ -55,7 +136,39 @@ func (p *Source) getDevice(devEui string) (*csv4api.Device, error) {
 	if err != nil {
 		return nil, errAPI.WithCause(err)
 	}
-	return resp.Device, nil
+	return resp, nil
+}
+
+// deviceLocation returns the location of a ChirpStack device. ChirpStack does not have a
+// device location, so the location is read from the `latitude`, `longitude` and `altitude`
+// device tags or variables. Tags take precedence over variables.
+func deviceLocation(csdev *csv4api.Device) *ttnpb.Location {
+	value := func(key string) (float64, bool) {
+		s, ok := csdev.Tags[key]
+		if !ok {
+			s, ok = csdev.Variables[key]
+		}
+		if !ok {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, err == nil
+	}
+	latitude, ok := value("latitude")
+	if !ok {
+		return nil
+	}
+	longitude, ok := value("longitude")
+	if !ok {
+		return nil
+	}
+	altitude, _ := value("altitude")
+	return &ttnpb.Location{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Altitude:  int32(math.Round(altitude)),
+		Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+	}
 }
 
 func (p *Source) getApplication(application string) (*csv4api.Application, error) {
@@ -97,9 +210,10 @@ func (p *Source) getApplicationIDByName(name string) (string, error) {
 	offset := uint32(0)
 	for {
 		resp, err := client.List(p.ctx, &csv4api.ListApplicationsRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: name,
+			TenantId: p.TenantID,
+			Limit:    limit,
+			Offset:   offset,
+			Search:   name,
 		})
 		if err != nil {
 			return "", err
@@ -139,3 +253,40 @@ func (p *Source) getActivation(devEui string) (*csv4api.DeviceActivation, error)
 	}
 	return resp.DeviceActivation, err
 }
+
+// applicationIterator iterates over the applications of a ChirpStack tenant.
+type applicationIterator struct {
+	ctx      context.Context
+	client   csv4api.ApplicationServiceClient
+	tenantID string
+
+	items  []string
+	offset uint32
+	done   bool
+}
+
+// Next implements iterator.Iterator.
+func (it *applicationIterator) Next() (string, error) {
+	for len(it.items) == 0 {
+		if it.done {
+			return "", io.EOF
+		}
+		resp, err := it.client.List(it.ctx, &csv4api.ListApplicationsRequest{
+			TenantId: it.tenantID,
+			Limit:    limit,
+			Offset:   it.offset,
+		})
+		if err != nil {
+			return "", errAPI.WithCause(err)
+		}
+		for _, appListItem := range resp.Result {
+			it.items = append(it.items, appListItem.Id)
+		}
+		if it.offset += limit; it.offset >= resp.TotalCount {
+			it.done = true
+		}
+	}
+	item := it.items[0]
+	it.items = it.items[1:]
+	return item, nil
+}
@@ -0,0 +1,201 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chirpstack_test
+
+import (
+	"testing"
+
+	csv4api "github.com/chirpstack/chirpstack/api/go/v4/api"
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+func TestJoinEUI(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		joinEUI  string
+		flag     string
+		expected []byte
+	}{
+		{
+			name:     "Device",
+			joinEUI:  "0807060504030201",
+			flag:     "0102030405060708",
+			expected: []byte{0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01},
+		},
+		{
+			name:     "Fallback",
+			flag:     "0102030405060708",
+			expected: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		},
+		{
+			// ChirpStack returns a zero JoinEUI for devices without JoinEUI.
+			name:     "FallbackForZero",
+			joinEUI:  "0000000000000000",
+			flag:     "0102030405060708",
+			expected: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		},
+		{
+			name: "None",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			fake.devices.devices[testDevEUI].Device.JoinEui = tc.joinEUI
+			src := newSource(t, fake, map[string]string{"join-eui": tc.flag})
+
+			dev, err := src.ExportDevice(testDevEUI)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			a.So(dev.Ids.JoinEui, should.Resemble, tc.expected)
+		})
+	}
+}
+
+func TestDeviceLocation(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		tags      map[string]string
+		variables map[string]string
+		expected  *ttnpb.Location
+	}{
+		{
+			name: "Tags",
+			tags: map[string]string{"latitude": "52.37", "longitude": "4.89", "altitude": "10.4"},
+			expected: &ttnpb.Location{
+				Latitude:  52.37,
+				Longitude: 4.89,
+				Altitude:  10,
+				Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+			},
+		},
+		{
+			name:      "Variables",
+			variables: map[string]string{"latitude": "52.37", "longitude": " 4.89 "},
+			expected: &ttnpb.Location{
+				Latitude:  52.37,
+				Longitude: 4.89,
+				Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+			},
+		},
+		{
+			// Tags take precedence over variables.
+			name:      "TagsAndVariables",
+			tags:      map[string]string{"latitude": "52.37", "longitude": "4.89"},
+			variables: map[string]string{"latitude": "48.85", "longitude": "2.35", "altitude": "35"},
+			expected: &ttnpb.Location{
+				Latitude:  52.37,
+				Longitude: 4.89,
+				Altitude:  35,
+				Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+			},
+		},
+		{
+			name: "NoLongitude",
+			tags: map[string]string{"latitude": "52.37"},
+		},
+		{
+			name: "InvalidLatitude",
+			tags: map[string]string{"latitude": "north", "longitude": "4.89"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			fake.devices.devices[testDevEUI].Device.Tags = tc.tags
+			fake.devices.devices[testDevEUI].Device.Variables = tc.variables
+			src := newSource(t, fake, nil)
+
+			dev, err := src.ExportDevice(testDevEUI)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			if tc.expected == nil {
+				a.So(dev.Locations, should.BeEmpty)
+				return
+			}
+			location := dev.Locations["user"]
+			if !a.So(location, should.NotBeNil) {
+				t.FailNow()
+			}
+			a.So(location.Latitude, should.Equal, tc.expected.Latitude)
+			a.So(location.Longitude, should.Equal, tc.expected.Longitude)
+			a.So(location.Altitude, should.Equal, tc.expected.Altitude)
+			a.So(location.Source, should.Equal, tc.expected.Source)
+		})
+	}
+}
+
+func TestDeviceStatus(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     *csv4api.DeviceStatus
+		powerState ttnpb.PowerState
+		battery    *float32
+		margin     int32
+	}{
+		{
+			name: "NoStatus",
+		},
+		{
+			name:       "Battery",
+			status:     &csv4api.DeviceStatus{Margin: 7, BatteryLevel: 50},
+			powerState: ttnpb.PowerState_POWER_BATTERY,
+			battery:    float32Ptr(0.5),
+			margin:     7,
+		},
+		{
+			name:       "ExternalPower",
+			status:     &csv4api.DeviceStatus{Margin: -3, ExternalPowerSource: true},
+			powerState: ttnpb.PowerState_POWER_EXTERNAL,
+			margin:     -3,
+		},
+		{
+			// ChirpStack reports a negative battery level if the level is unknown.
+			name:   "UnknownBattery",
+			status: &csv4api.DeviceStatus{Margin: 5, BatteryLevel: -1},
+			margin: 5,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			fake := newFakeChirpStack()
+			fake.devices.devices[testDevEUI].DeviceStatus = tc.status
+			src := newSource(t, fake, nil)
+
+			dev, err := src.ExportDevice(testDevEUI)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			a.So(dev.PowerState, should.Equal, tc.powerState)
+			a.So(dev.DownlinkMargin, should.Equal, tc.margin)
+			if tc.battery == nil {
+				a.So(dev.BatteryPercentage, should.BeNil)
+			} else if a.So(dev.BatteryPercentage, should.NotBeNil) {
+				a.So(dev.BatteryPercentage.GetValue(), should.Equal, *tc.battery)
+			}
+			// ChirpStack does not store when the device status was received.
+			a.So(dev.LastDevStatusReceivedAt, should.BeNil)
+		})
+	}
+}
+
+func float32Ptr(v float32) *float32 {
+	return &v
+}
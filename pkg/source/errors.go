@@ -20,4 +20,7 @@ var (
 	ErrNotRegistered     = errors.DefineInvalidArgument("not_registered", "source `{source}` is not registered")
 	ErrAlreadyRegistered = errors.DefineInvalidArgument("already_registered", "source `{source}` is already registered")
 	ErrNoSource          = errors.DefineInvalidArgument("no_source", "no source")
+
+	ErrGatewaysNotSupported            = errors.DefineUnimplemented("gateways_not_supported", "source `{source}` does not support gateways")
+	ErrApplicationSettingsNotSupported = errors.DefineUnimplemented("application_settings_not_supported", "source `{source}` does not support application settings")
 )
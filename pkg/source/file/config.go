@@ -0,0 +1,159 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
+	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/csv"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+// Format is the format of a file.
+type Format string
+
+const (
+	// FormatCSV is a CSV file with a header.
+	FormatCSV Format = "csv"
+	// FormatJSON is a JSON array of devices.
+	FormatJSON Format = "json"
+	// FormatNDJSON is a file with a JSON device on each line.
+	FormatNDJSON Format = "ndjson"
+)
+
+// formatFromPath returns the format of the file by its extension.
+func formatFromPath(path string) (Format, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv", ".tsv", ".txt":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	default:
+		return "", errInvalidFormat.WithAttributes("format", ext)
+	}
+}
+
+type Config struct {
+	src source.Config
+
+	path             string
+	format           string
+	fieldMappingPath string
+	all              bool
+
+	appID           string
+	frequencyPlanID string
+	macVersion      string
+
+	CSV csv.Config
+
+	flags   *pflag.FlagSet
+	fpStore *frequencyplans.Store
+}
+
+// NewConfig returns a new file source configuration.
+func NewConfig() *Config {
+	config := &Config{
+		flags: &pflag.FlagSet{},
+	}
+	config.flags.StringVar(&config.path,
+		"path",
+		os.Getenv("FILE_PATH"),
+		"Path to the CSV, JSON or NDJSON file with the devices")
+	config.flags.StringVar(&config.format,
+		"format",
+		os.Getenv("FILE_FORMAT"),
+		"Format of the file (csv, json or ndjson). If not set, the format is derived from the file extension")
+	config.flags.StringVar(&config.fieldMappingPath,
+		"field-mapping-file",
+		os.Getenv("FILE_FIELD_MAPPING_FILE"),
+		`(optional) Path to a file that maps fields of the file to device fields, one mapping per line.
+Fields that are not mapped are used as is`)
+	config.flags.StringVar(&config.CSV.Delimiter,
+		"csv-delimiter",
+		util.EnvString("FILE_CSV_DELIMITER", csv.DefaultDelimiter),
+		"Field delimiter of CSV files. Use \\t or tab for tab separated files")
+	config.flags.StringVar(&config.CSV.Encoding,
+		"csv-encoding",
+		util.EnvString("FILE_CSV_ENCODING", csv.DefaultEncoding),
+		"Character encoding of CSV files, for example utf-8, utf-16le or windows-1252")
+	config.flags.BoolVar(&config.all,
+		"all",
+		false,
+		`Export all devices in the file. This is only used by the application command.
+Without this flag, the application command exports the devices of the given application IDs`)
+	config.flags.StringVar(&config.appID,
+		"app-id",
+		os.Getenv("APP_ID"),
+		"Application ID for devices without application_id field")
+	config.flags.StringVar(&config.frequencyPlanID,
+		"frequency-plan-id",
+		os.Getenv("FREQUENCY_PLAN_ID"),
+		"Frequency Plan ID for devices without frequency_plan_id field")
+	config.flags.StringVar(&config.macVersion,
+		"mac-version",
+		os.Getenv("FILE_MAC_VERSION"),
+		"LoRaWAN MAC version for devices without mac_version field, for example 1.0.3")
+	return config
+}
+
+// Initialize the configuration.
+func (c *Config) Initialize(src source.Config) error {
+	c.src = src
+
+	if c.path == "" {
+		return errNoPath.New()
+	}
+	if c.format == "" {
+		format, err := formatFromPath(c.path)
+		if err != nil {
+			return err
+		}
+		c.format = string(format)
+	}
+	switch Format(c.format) {
+	case FormatCSV, FormatJSON, FormatNDJSON:
+	default:
+		return errInvalidFormat.WithAttributes("format", c.format)
+	}
+	// The field mapping renames the columns of CSV files, and the fields of JSON files.
+	c.CSV.ColumnMappingPath = c.fieldMappingPath
+	if err := c.CSV.Initialize(); err != nil {
+		return err
+	}
+
+	fpFetcher, err := fetch.FromHTTP(http.DefaultClient, src.FrequencyPlansURL)
+	if err != nil {
+		return err
+	}
+	c.fpStore = frequencyplans.NewStore(fpFetcher)
+
+	return nil
+}
+
+// Flags returns the flags for the configuration.
+func (c *Config) Flags() *pflag.FlagSet {
+	return c.flags
+}
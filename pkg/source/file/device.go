@@ -0,0 +1,311 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+	"go.uber.org/zap"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+// Device fields.
+const (
+	FieldDevEUI          = "dev_eui"
+	FieldJoinEUI         = "join_eui"
+	FieldDeviceID        = "device_id"
+	FieldApplicationID   = "application_id"
+	FieldName            = "name"
+	FieldDescription     = "description"
+	FieldFrequencyPlanID = "frequency_plan_id"
+	FieldMACVersion      = "mac_version"
+	FieldPHYVersion      = "phy_version"
+	FieldActivation      = "activation"
+	FieldClass           = "class"
+	FieldAppKey          = "app_key"
+	FieldNwkKey          = "nwk_key"
+	FieldDevAddr         = "dev_addr"
+	FieldAppSKey         = "app_s_key"
+	FieldNwkSKey         = "nwk_s_key"
+	FieldFNwkSIntKey     = "f_nwk_s_int_key"
+	FieldSNwkSIntKey     = "s_nwk_s_int_key"
+	FieldNwkSEncKey      = "nwk_s_enc_key"
+	FieldFCntUp          = "f_cnt_up"
+	FieldFCntDown        = "f_cnt_down"
+	FieldAFCntDown       = "a_f_cnt_down"
+	FieldLatitude        = "latitude"
+	FieldLongitude       = "longitude"
+	FieldAltitude        = "altitude"
+
+	// FieldAttributePrefix is the prefix of fields that are exported as attributes.
+	FieldAttributePrefix = "attributes."
+)
+
+// parsePHYVersion parses a PHY version by name, for example `RP001_V1_0_3_REV_A`.
+func parsePHYVersion(v string) (ttnpb.PHYVersion, error) {
+	phy, ok := ttnpb.PHYVersion_value[strings.ToUpper(v)]
+	if !ok || phy == int32(ttnpb.PHYVersion_PHY_UNKNOWN) {
+		return ttnpb.PHYVersion_PHY_UNKNOWN, errInvalidPHYVersion.WithAttributes("phy_version", v)
+	}
+	return ttnpb.PHYVersion(phy), nil
+}
+
+// parseUint32 parses the field as an unsigned 32-bit integer.
+func (d Device) parseUint32(field string) (uint32, error) {
+	v, err := strconv.ParseUint(d.Fields[field], 10, 32)
+	if err != nil {
+		return 0, errInvalidField.WithAttributes("field", field).WithCause(err)
+	}
+	return uint32(v), nil
+}
+
+// parseFloat parses the field as a floating point number.
+func (d Device) parseFloat(field string) (float64, error) {
+	v, err := strconv.ParseFloat(d.Fields[field], 64)
+	if err != nil {
+		return 0, errInvalidField.WithAttributes("field", field).WithCause(err)
+	}
+	return v, nil
+}
+
+// get returns the value of the first field that is set.
+func (d Device) get(fields ...string) string {
+	for _, field := range fields {
+		if v := d.Fields[field]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// DevEUI returns the DevEUI of the device.
+func (d Device) DevEUI() (types.EUI64, error) {
+	var devEUI types.EUI64
+	if err := devEUI.UnmarshalText([]byte(d.Fields[FieldDevEUI])); err != nil {
+		return types.EUI64{}, errInvalidField.WithAttributes("field", FieldDevEUI).WithCause(err)
+	}
+	return devEUI, nil
+}
+
+// ApplicationID returns the application ID of the device, or applicationID if the device has no
+// application ID.
+func (d Device) ApplicationID(applicationID string) string {
+	if appID := d.Fields[FieldApplicationID]; appID != "" {
+		return appID
+	}
+	return applicationID
+}
+
+// EndDevice converts the device to a TTS device. The application ID, frequency plan ID and MAC version
+// are used for devices without these fields.
+func (d Device) EndDevice(
+	logger *zap.SugaredLogger, fpStore *frequencyplans.Store, applicationID, frequencyPlanID, macVersion string,
+) (*ttnpb.EndDevice, error) {
+	devEUI, err := d.DevEUI()
+	if err != nil {
+		return nil, err
+	}
+	ret := &ttnpb.EndDevice{
+		Name:            d.Fields[FieldName],
+		Description:     d.Fields[FieldDescription],
+		FrequencyPlanId: d.get(FieldFrequencyPlanID),
+		Ids: &ttnpb.EndDeviceIdentifiers{
+			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: d.ApplicationID(applicationID)},
+			DeviceId:       d.Fields[FieldDeviceID],
+			DevEui:         devEUI.Bytes(),
+		},
+		MacSettings: &ttnpb.MACSettings{},
+	}
+	if ret.Ids.ApplicationIds.ApplicationId == "" {
+		return nil, errNoAppID.New()
+	}
+	if ret.FrequencyPlanId == "" {
+		ret.FrequencyPlanId = frequencyPlanID
+	}
+	if ret.FrequencyPlanId == "" {
+		return nil, errNoFrequencyPlanID.New()
+	}
+
+	if v := d.get(FieldMACVersion); v != "" {
+		macVersion = v
+	}
+	if macVersion == "" {
+		return nil, errNoMACVersion.New()
+	}
+	if ret.LorawanVersion, ret.LorawanPhyVersion, err = session.ParseMACVersion(macVersion); err != nil {
+		return nil, err
+	}
+	if v := d.Fields[FieldPHYVersion]; v != "" {
+		if ret.LorawanPhyVersion, err = parsePHYVersion(v); err != nil {
+			return nil, err
+		}
+	}
+
+	class := strings.ToUpper(d.Fields[FieldClass])
+	ret.SupportsClassB = strings.Contains(class, "B")
+	ret.SupportsClassC = strings.Contains(class, "C")
+
+	switch activation := strings.ToUpper(d.Fields[FieldActivation]); activation {
+	case "OTAA":
+		ret.SupportsJoin = true
+	case "ABP":
+	case "":
+		ret.SupportsJoin = d.get(FieldAppKey, FieldNwkKey) != ""
+	default:
+		return nil, errInvalidActivation.WithAttributes("activation", d.Fields[FieldActivation])
+	}
+
+	d.setAttributes(logger, ret)
+	if err := d.setLocation(ret); err != nil {
+		return nil, err
+	}
+	if ret.SupportsJoin {
+		if err := d.setRootKeys(ret); err != nil {
+			return nil, err
+		}
+	}
+
+	hasSession := d.Fields[FieldDevAddr] != "" && d.Fields[FieldAppSKey] != "" &&
+		d.get(FieldNwkSKey, FieldFNwkSIntKey) != ""
+	if hasSession || !ret.SupportsJoin {
+		ret.Session, err = session.New(ret, session.Keys{
+			DevAddr:     d.Fields[FieldDevAddr],
+			AppSKey:     d.Fields[FieldAppSKey],
+			FNwkSIntKey: d.get(FieldFNwkSIntKey, FieldNwkSKey),
+			SNwkSIntKey: d.Fields[FieldSNwkSIntKey],
+			NwkSEncKey:  d.Fields[FieldNwkSEncKey],
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := d.setFrameCounters(ret); err != nil {
+			return nil, err
+		}
+		if ret.MacState, err = session.NewMACState(ret, fpStore); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// setAttributes sets the attributes of the device from the fields with the attribute prefix.
+// Fields that are not valid attributes are skipped with a warning.
+func (d Device) setAttributes(logger *zap.SugaredLogger, dev *ttnpb.EndDevice) {
+	var attributes util.Attributes
+	for _, field := range slices.Sorted(maps.Keys(d.Fields)) {
+		key, ok := strings.CutPrefix(field, FieldAttributePrefix)
+		if !ok || d.Fields[field] == "" {
+			continue
+		}
+		if err := attributes.Set(key, d.Fields[field]); err != nil {
+			logger.Warnw("Skip attribute", "position", d.Position, "field", field, "error", err)
+		}
+	}
+	dev.Attributes = attributes.Map()
+}
+
+// setLocation sets the location of the device if the latitude and longitude are set.
+func (d Device) setLocation(dev *ttnpb.EndDevice) error {
+	if d.Fields[FieldLatitude] == "" || d.Fields[FieldLongitude] == "" {
+		return nil
+	}
+	latitude, err := d.parseFloat(FieldLatitude)
+	if err != nil {
+		return err
+	}
+	longitude, err := d.parseFloat(FieldLongitude)
+	if err != nil {
+		return err
+	}
+	location := &ttnpb.Location{
+		Latitude:  latitude,
+		Longitude: longitude,
+		Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+	}
+	if d.Fields[FieldAltitude] != "" {
+		altitude, err := d.parseFloat(FieldAltitude)
+		if err != nil {
+			return err
+		}
+		location.Altitude = int32(math.Round(altitude))
+	}
+	dev.Locations = map[string]*ttnpb.Location{
+		"user": location,
+	}
+	return nil
+}
+
+// setRootKeys sets the JoinEUI and root keys of an OTAA device. LoRaWAN 1.1 devices use the AppKey as
+// NwkKey if no NwkKey is set.
+func (d Device) setRootKeys(dev *ttnpb.EndDevice) error {
+	if d.Fields[FieldJoinEUI] == "" {
+		return errNoJoinEUI.New()
+	}
+	var (
+		joinEUI types.EUI64
+		err     error
+	)
+	if err := joinEUI.UnmarshalText([]byte(d.Fields[FieldJoinEUI])); err != nil {
+		return errInvalidField.WithAttributes("field", FieldJoinEUI).WithCause(err)
+	}
+	dev.Ids.JoinEui = joinEUI.Bytes()
+
+	dev.RootKeys = &ttnpb.RootKeys{AppKey: &ttnpb.KeyEnvelope{}}
+	dev.RootKeys.AppKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, d.get(FieldAppKey, FieldNwkKey))
+	if err != nil {
+		return errInvalidField.WithAttributes("field", FieldAppKey).WithCause(err)
+	}
+	if dev.LorawanVersion.Compare(ttnpb.MACVersion_MAC_V1_1) >= 0 {
+		dev.RootKeys.NwkKey = &ttnpb.KeyEnvelope{}
+		dev.RootKeys.NwkKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, d.get(FieldNwkKey, FieldAppKey))
+		if err != nil {
+			return errInvalidField.WithAttributes("field", FieldNwkKey).WithCause(err)
+		}
+	}
+	return nil
+}
+
+// setFrameCounters sets the frame counters of the session. LoRaWAN 1.0.x devices use the same frame
+// counter for application and network downlinks.
+func (d Device) setFrameCounters(dev *ttnpb.EndDevice) error {
+	var err error
+	if d.Fields[FieldFCntUp] != "" {
+		if dev.Session.LastFCntUp, err = d.parseUint32(FieldFCntUp); err != nil {
+			return err
+		}
+	}
+	if d.Fields[FieldFCntDown] != "" {
+		if dev.Session.LastNFCntDown, err = d.parseUint32(FieldFCntDown); err != nil {
+			return err
+		}
+		dev.Session.LastAFCntDown = dev.Session.LastNFCntDown
+	}
+	if d.Fields[FieldAFCntDown] != "" {
+		if dev.Session.LastAFCntDown, err = d.parseUint32(FieldAFCntDown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
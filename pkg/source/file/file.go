@@ -0,0 +1,49 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package file provides a source that reads devices from CSV, JSON or NDJSON files, for example
+// spreadsheets or exports of network servers without an API.
+package file
+
+import (
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+)
+
+var (
+	errNoPath            = errors.DefineInvalidArgument("no_path", "no file path")
+	errInvalidFormat     = errors.DefineInvalidArgument("invalid_format", "invalid format `{format}`")
+	errNoAppID           = errors.DefineInvalidArgument("no_app_id", "no app id")
+	errNoFrequencyPlanID = errors.DefineInvalidArgument("no_frequency_plan_id", "no frequency plan ID")
+	errNoMACVersion      = errors.DefineInvalidArgument("no_mac_version", "no MAC version")
+	errNoJoinEUI         = errors.DefineInvalidArgument("no_join_eui", "no join eui")
+	errNoDeviceFound     = errors.DefineInvalidArgument("no_device_found", "no device with eui `{eui}` found")
+	errDuplicateDevice   = errors.DefineAlreadyExists("duplicate_device", "duplicate device with eui `{eui}`")
+	errInvalidPHYVersion = errors.DefineInvalidArgument("invalid_phy_version", "invalid PHY version `{phy_version}`")
+	errInvalidActivation = errors.DefineInvalidArgument("invalid_activation", "invalid activation `{activation}`")
+	errInvalidField      = errors.DefineInvalidArgument("invalid_field", "invalid field `{field}`")
+	errInvalidDevice     = errors.DefineInvalidArgument("invalid_device", "invalid device at {position}")
+	errInvalidJSON       = errors.DefineInvalidArgument("invalid_json", "invalid JSON at {position}")
+)
+
+func init() {
+	cfg := NewConfig()
+
+	source.RegisterSource(source.Registration{
+		Name:        "file",
+		Description: "Migrate from CSV, JSON or NDJSON files",
+		FlagSet:     cfg.Flags(),
+		Create:      createNewSource(cfg),
+	})
+}
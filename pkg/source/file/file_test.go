@@ -0,0 +1,195 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack-migrate/pkg/csv"
+	. "go.thethings.network/lorawan-stack-migrate/pkg/source/file"
+	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
+	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.uber.org/zap"
+)
+
+func readDevices(t *testing.T, path string, format Format) []Device {
+	t.Helper()
+	config := csv.Config{ColumnMappingPath: "./testdata/fields.csv"}
+	if err := config.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize CSV config: %v", err)
+	}
+	devs, err := ReadDevices(path, format, config)
+	if err != nil {
+		t.Fatalf("Failed to read devices: %v", err)
+	}
+	return devs
+}
+
+func TestReadDevices(t *testing.T) {
+	expected := []map[string]string{
+		{
+			FieldDevEUI:                       "0102030405060708",
+			FieldJoinEUI:                      "0102030405060700",
+			FieldAppKey:                       "01020304050607080102030405060708",
+			FieldMACVersion:                   "1.0.3",
+			FieldClass:                        "A",
+			FieldLatitude:                     "52.37",
+			FieldLongitude:                    "4.89",
+			FieldAltitude:                     "10",
+			FieldAttributePrefix + "building": "Building A",
+		},
+		{
+			FieldDevEUI:                       "0102030405060709",
+			FieldDevAddr:                      "01020304",
+			FieldAppSKey:                      "01020304050607080102030405060708",
+			FieldNwkSKey:                      "08070605040302010807060504030201",
+			FieldFCntUp:                       "20",
+			FieldFCntDown:                     "10",
+			FieldMACVersion:                   "1.0.2b",
+			FieldClass:                        "C",
+			FieldApplicationID:                "other-app",
+			FieldAttributePrefix + "building": "Building B",
+		},
+	}
+	for _, tc := range []struct {
+		path      string
+		format    Format
+		positions []string
+	}{
+		{
+			path:      "./testdata/devices.csv",
+			format:    FormatCSV,
+			positions: []string{"line 2", "line 3"},
+		},
+		{
+			path:      "./testdata/devices.json",
+			format:    FormatJSON,
+			positions: []string{"device 1", "device 2"},
+		},
+		{
+			path:      "./testdata/devices.ndjson",
+			format:    FormatNDJSON,
+			positions: []string{"line 1", "line 3"},
+		},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			a := assertions.New(t)
+			devs := readDevices(t, tc.path, tc.format)
+			if !a.So(devs, should.HaveLength, len(expected)) {
+				t.FailNow()
+			}
+			for i, dev := range devs {
+				a.So(dev.Position, should.Equal, tc.positions[i])
+				a.So(dev.Fields, should.Resemble, expected[i])
+			}
+		})
+	}
+}
+
+func TestReadUppercaseFields(t *testing.T) {
+	expected := map[string]string{
+		FieldDevEUI:                       "0102030405060708",
+		FieldAppKey:                       "01020304050607080102030405060708",
+		FieldAttributePrefix + "building": "Building A",
+	}
+	for _, tc := range []struct {
+		format  Format
+		content string
+	}{
+		{
+			format:  FormatCSV,
+			content: "DEV_EUI,App_Key,ATTRIBUTES.Building\n0102030405060708,01020304050607080102030405060708,Building A\n",
+		},
+		{
+			format:  FormatJSON,
+			content: `[{"DEV_EUI":"0102030405060708","App_Key":"01020304050607080102030405060708","ATTRIBUTES":{"Building":"Building A"}}]`,
+		},
+		{
+			format:  FormatNDJSON,
+			content: `{"DEV_EUI":"0102030405060708","App_Key":"01020304050607080102030405060708","ATTRIBUTES":{"Building":"Building A"}}`,
+		},
+	} {
+		t.Run(string(tc.format), func(t *testing.T) {
+			a := assertions.New(t)
+			path := filepath.Join(t.TempDir(), "devices")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			config := csv.Config{}
+			if err := config.Initialize(); err != nil {
+				t.Fatalf("Failed to initialize CSV config: %v", err)
+			}
+			devs, err := ReadDevices(path, tc.format, config)
+			if !a.So(err, should.BeNil) || !a.So(devs, should.HaveLength, 1) {
+				t.FailNow()
+			}
+			a.So(devs[0].Fields, should.Resemble, expected)
+		})
+	}
+}
+
+func TestEndDevice(t *testing.T) {
+	a := assertions.New(t)
+	devs := readDevices(t, "./testdata/devices.csv", FormatCSV)
+
+	fpFetcher, err := fetch.FromHTTP(
+		http.DefaultClient,
+		"https://raw.githubusercontent.com/TheThingsNetwork/lorawan-frequency-plans/master",
+	)
+	if err != nil {
+		t.Fatalf("Failed to create fetcher: %v", err)
+	}
+	fpStore := frequencyplans.NewStore(fpFetcher)
+
+	// OTAA device without session.
+	dev, err := devs[0].EndDevice(zap.NewNop().Sugar(), fpStore, "test-app", "EU_863_870", "")
+	if err != nil {
+		t.Fatalf("Failed to convert device: %v", err)
+	}
+	a.So(dev.Ids.ApplicationIds.ApplicationId, should.Equal, "test-app")
+	a.So(dev.Ids.JoinEui, should.Resemble, []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x00})
+	a.So(dev.SupportsJoin, should.BeTrue)
+	a.So(dev.LorawanVersion, should.Equal, ttnpb.MACVersion_MAC_V1_0_3)
+	a.So(dev.RootKeys.GetAppKey().GetKey(), should.HaveLength, 16)
+	a.So(dev.Session, should.BeNil)
+	a.So(dev.Attributes, should.Resemble, map[string]string{"building": "Building A"})
+	a.So(dev.Locations["user"].Altitude, should.Equal, 10)
+
+	// ABP device with session.
+	dev, err = devs[1].EndDevice(zap.NewNop().Sugar(), fpStore, "test-app", "EU_863_870", "")
+	if err != nil {
+		t.Fatalf("Failed to convert device: %v", err)
+	}
+	a.So(dev.Ids.ApplicationIds.ApplicationId, should.Equal, "other-app")
+	a.So(dev.SupportsJoin, should.BeFalse)
+	a.So(dev.SupportsClassC, should.BeTrue)
+	a.So(dev.LorawanPhyVersion, should.Equal, ttnpb.PHYVersion_RP001_V1_0_2_REV_B)
+	a.So(dev.Session.DevAddr, should.Resemble, []byte{0x01, 0x02, 0x03, 0x04})
+	a.So(dev.Session.LastFCntUp, should.Equal, 20)
+	a.So(dev.Session.LastNFCntDown, should.Equal, 10)
+	a.So(dev.Session.LastAFCntDown, should.Equal, 10)
+	a.So(dev.MacState, should.NotBeNil)
+
+	// Missing MAC version.
+	delete(devs[1].Fields, FieldMACVersion)
+	_, err = devs[1].EndDevice(zap.NewNop().Sugar(), fpStore, "test-app", "EU_863_870", "")
+	a.So(err, should.NotBeNil)
+}
@@ -0,0 +1,186 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/csv"
+)
+
+// maxLineSize is the maximum size of a line of an NDJSON file.
+const maxLineSize = 1 << 20
+
+// Device is a device that is read from a file.
+type Device struct {
+	// Position is the line or index of the device in the file.
+	Position string
+	// Fields contains the values of the device fields.
+	Fields map[string]string
+}
+
+// ReadDevices reads the devices from the file. The field mapping of the CSV configuration renames the
+// columns of CSV files and the fields of JSON files. Fields of nested JSON objects are joined with a dot,
+// for example `location.latitude`. Field names are converted to lowercase, so that fields are matched
+// case-insensitively.
+func ReadDevices(path string, format Format, config csv.Config) ([]Device, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch format {
+	case FormatCSV:
+		return readCSV(f, config)
+	case FormatJSON:
+		return readJSON(f, config.ColumnMapping)
+	case FormatNDJSON:
+		return readNDJSON(f, config.ColumnMapping)
+	default:
+		return nil, errInvalidFormat.WithAttributes("format", format)
+	}
+}
+
+func readCSV(r io.Reader, config csv.Config) ([]Device, error) {
+	reader, err := config.NewReader(r, FieldDevEUI)
+	if err != nil {
+		return nil, err
+	}
+	var devs []Device
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return devs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		values := lowerFields(record.Values())
+		// Empty values are not set, like missing fields of JSON objects.
+		maps.DeleteFunc(values, func(_, value string) bool { return value == "" })
+		devs = append(devs, Device{
+			Position: fmt.Sprintf("line %d", record.Line),
+			Fields:   values,
+		})
+	}
+}
+
+func readJSON(r io.Reader, mapping map[string]string) ([]Device, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errInvalidJSON.WithAttributes("position", "start of file")
+	}
+	var devs []Device
+	for i := 1; dec.More(); i++ {
+		position := fmt.Sprintf("device %d", i)
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil {
+			return nil, errInvalidJSON.WithAttributes("position", position).WithCause(err)
+		}
+		devs = append(devs, Device{
+			Position: position,
+			Fields:   fields(obj, mapping),
+		})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, errInvalidJSON.WithAttributes("position", "end of file").WithCause(err)
+	}
+	return devs, nil
+}
+
+func readNDJSON(r io.Reader, mapping map[string]string) ([]Device, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineSize)
+	var devs []Device
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		position := fmt.Sprintf("line %d", line)
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil {
+			return nil, errInvalidJSON.WithAttributes("position", position).WithCause(err)
+		}
+		devs = append(devs, Device{
+			Position: position,
+			Fields:   fields(obj, mapping),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return devs, nil
+}
+
+// fields returns the fields of a JSON object, renamed with the mapping.
+func fields(obj map[string]any, mapping map[string]string) map[string]string {
+	flat := make(map[string]string)
+	flatten("", obj, flat)
+	ret := make(map[string]string, len(flat))
+	for key, value := range flat {
+		if field, ok := mapping[key]; ok {
+			key = field
+		}
+		ret[key] = value
+	}
+	return lowerFields(ret)
+}
+
+// lowerFields returns the fields with lowercase names. Fields of which the name is already lowercase
+// take precedence over other fields with the same lowercase name.
+func lowerFields(fields map[string]string) map[string]string {
+	ret := make(map[string]string, len(fields))
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		lower := strings.ToLower(key)
+		if _, ok := fields[lower]; ok && key != lower {
+			continue
+		}
+		if _, ok := ret[lower]; ok && key != lower {
+			continue
+		}
+		ret[lower] = fields[key]
+	}
+	return ret
+}
+
+// flatten adds the values of the JSON object to ret. Arrays and null values are ignored.
+func flatten(prefix string, obj map[string]any, ret map[string]string) {
+	for key, value := range obj {
+		key = prefix + key
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(key+".", v, ret)
+		case string:
+			ret[key] = v
+		case json.Number:
+			ret[key] = v.String()
+		case bool:
+			ret[key] = fmt.Sprint(v)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+	"os"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/iterator"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+)
+
+// allApplications is the application ID that is used to export all devices.
+const allApplications = "all"
+
+type Source struct {
+	*Config
+
+	devices map[types.EUI64]Device
+	// devEUIs contains the DevEUIs in the order of the file.
+	devEUIs []types.EUI64
+}
+
+func createNewSource(cfg *Config) source.CreateSource {
+	return func(ctx context.Context, src source.Config) (source.Source, error) {
+		if err := cfg.Initialize(src); err != nil {
+			return nil, err
+		}
+		devs, err := ReadDevices(cfg.path, Format(cfg.format), cfg.CSV)
+		if err != nil {
+			return nil, err
+		}
+		s := Source{
+			Config:  cfg,
+			devices: make(map[types.EUI64]Device, len(devs)),
+		}
+		for _, dev := range devs {
+			devEUI, err := dev.DevEUI()
+			if err != nil {
+				return nil, errInvalidDevice.WithAttributes("position", dev.Position).WithCause(err)
+			}
+			if _, ok := s.devices[devEUI]; ok {
+				return nil, errInvalidDevice.WithAttributes("position", dev.Position).WithCause(
+					errDuplicateDevice.WithAttributes("eui", devEUI),
+				)
+			}
+			s.devices[devEUI] = dev
+			s.devEUIs = append(s.devEUIs, devEUI)
+		}
+		src.Logger.Debugw("Read devices", "path", cfg.path, "format", cfg.format, "count", len(devs))
+		return s, nil
+	}
+}
+
+// Iterator implements source.Source.
+func (s Source) Iterator(isApplication bool) iterator.Iterator {
+	if !isApplication {
+		return iterator.NewReaderIterator(os.Stdin, '\n')
+	}
+	if s.all {
+		return iterator.NewListIterator(
+			[]string{allApplications},
+		)
+	}
+	return iterator.NewNoopIterator()
+}
+
+// ExportDevice implements the source.Source interface.
+func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
+	var devEUI types.EUI64
+	if err := devEUI.UnmarshalText([]byte(devEUIString)); err != nil {
+		return nil, err
+	}
+	dev, ok := s.devices[devEUI]
+	if !ok {
+		return nil, errNoDeviceFound.WithAttributes("eui", devEUIString)
+	}
+	v3dev, err := dev.EndDevice(s.src.Logger, s.fpStore, s.appID, s.frequencyPlanID, s.macVersion)
+	if err != nil {
+		return nil, errInvalidDevice.WithAttributes("position", dev.Position).WithCause(err)
+	}
+	return v3dev, nil
+}
+
+// RangeDevices implements the source.Source interface.
+// Only the devices of the application are exported, unless all devices are exported.
+func (s Source) RangeDevices(appID string, f func(source.Source, string) error) error {
+	for _, devEUI := range s.devEUIs {
+		if appID != allApplications && s.devices[devEUI].ApplicationID(s.appID) != appID {
+			continue
+		}
+		if err := f(s, devEUI.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the Source interface.
+func (s Source) Close() error { return nil }
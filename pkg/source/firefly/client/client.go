@@ -25,20 +25,20 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"time"
 
+	"go.thethings.network/lorawan-stack-migrate/pkg/httpclient"
 	"go.thethings.network/lorawan-stack-migrate/pkg/log"
 	"go.thethings.network/lorawan-stack/v3/pkg/errors"
 )
 
-const defaultTimeout = 10 * time.Second
-
 // Config is the Firefly client configuration.
 type Config struct {
 	APIKey     string
 	Host       string
 	CACertPath string
 	UseHTTP    bool
+
+	HTTP httpclient.Config
 }
 
 // Client is a Firefly client.
@@ -69,11 +69,8 @@ func (cfg *Config) NewClient(ctx context.Context) (*Client, error) {
 	}
 	return &Client{
 		Config: cfg,
-		Client: &http.Client{
-			Transport: httpTransport,
-			Timeout:   defaultTimeout,
-		},
-		ctx: ctx,
+		Client: cfg.HTTP.New(httpTransport, log.FromContext(ctx)),
+		ctx:    ctx,
 	}, nil
 }
 
@@ -81,7 +78,7 @@ var (
 	errResourceNotFound     = errors.DefineNotFound("resource_not_found", "resource `{resource}` not found")
 	errServer               = errors.Define("server", "server error with code `{code}`")
 	errUnexpectedStatusCode = errors.Define("unexpected_status_code", "unexpected status code `{code}`")
-	errMultiplePackets      = errors.Define("multiple_packets", "multiple packets found for device")
+	errMultiplePackets      = errors.Define("multiple_packets", "`{len}` packets found for device")
 )
 
 // do executes an HTTP request.
@@ -102,16 +99,16 @@ func (c *Client) do(resource, method string, body []byte, params string) ([]byte
 		RawQuery: authAndParams,
 	}
 
-	logger := log.FromContext(c.ctx).With("url", url.String())
+	logger := log.FromContext(c.ctx).With("url", httpclient.RedactURL(&url))
 	logger.Debug("Request resource")
-	req, err := http.NewRequest(method, url.String(), bytes.NewReader(body))
-	req.Header.Set("Content-Type", "application/json")
+	req, err := http.NewRequestWithContext(c.ctx, method, url.String(), bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		return nil, httpclient.RedactError(err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 	res, err := c.Client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, httpclient.RedactError(err)
 	}
 	defer res.Body.Close()
 	body, err = io.ReadAll(res.Body)
@@ -160,7 +157,7 @@ func (c *Client) UpdateDeviceByEUI(eui string, dev Device) error {
 	return err
 }
 
-// GetLastPacket gets the last packet for a device.
+// GetLastPacket gets the last packet for a device, or nil if the device has not sent any packets.
 func (c *Client) GetLastPacket(eui string) (*Packet, error) {
 	body, err := c.do(fmt.Sprintf("devices/eui/%s/packets", eui), http.MethodGet, nil, "limit_to_last=1")
 	if err != nil {
@@ -172,15 +169,37 @@ func (c *Client) GetLastPacket(eui string) (*Packet, error) {
 	if err := json.Unmarshal(body, &wrapper); err != nil {
 		return nil, err
 	}
-	if len(wrapper.Packets) != 1 {
+	switch len(wrapper.Packets) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &wrapper.Packets[0], nil
+	default:
 		return nil, errMultiplePackets.WithAttributes("len", len(wrapper.Packets))
 	}
-	return &wrapper.Packets[0], nil
 }
 
-// GetAllDevices gets all devices that the API key has access to.
-func (c *Client) GetAllDevices() ([]Device, error) {
-	body, err := c.do("devices", http.MethodGet, nil, "")
+// GetPackets gets the last packets of all devices that the API key has access to.
+func (c *Client) GetPackets(limit int) ([]Packet, error) {
+	body, err := c.do("packets", http.MethodGet, nil, fmt.Sprintf("limit_to_last=%d", limit))
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Packets []Packet `json:"packets"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Packets, nil
+}
+
+// devicesPageSize is the number of devices that are requested per page.
+const devicesPageSize = 100
+
+// ListDevices gets a page of the devices that the API key has access to.
+func (c *Client) ListDevices(offset, limit int) ([]Device, error) {
+	body, err := c.do("devices", http.MethodGet, nil, fmt.Sprintf("offset=%d&limit=%d", offset, limit))
 	if err != nil {
 		return nil, err
 	}
@@ -192,3 +211,76 @@ func (c *Client) GetAllDevices() ([]Device, error) {
 	}
 	return wrapper.Device, nil
 }
+
+// GetAllDevices gets all devices that the API key has access to.
+func (c *Client) GetAllDevices() ([]Device, error) {
+	var (
+		devs []Device
+		seen = make(map[string]bool)
+	)
+	for offset := 0; ; offset += devicesPageSize {
+		page, err := c.ListDevices(offset, devicesPageSize)
+		if err != nil {
+			return nil, err
+		}
+		// Servers that do not support pagination return all devices on each page.
+		if len(page) > 0 && seen[page[0].EUI] {
+			return devs, nil
+		}
+		for _, dev := range page {
+			seen[dev.EUI] = true
+		}
+		devs = append(devs, page...)
+		if len(page) < devicesPageSize {
+			return devs, nil
+		}
+	}
+}
+
+// GetAllDeviceClasses gets all device classes that the API key has access to.
+func (c *Client) GetAllDeviceClasses() ([]DeviceClass, error) {
+	body, err := c.do("device_classes", http.MethodGet, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		DeviceClasses []DeviceClass `json:"device_classes"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.DeviceClasses, nil
+}
+
+// GetGatewayByEUI gets a gateway by the EUI, or nil if the response contains no gateway.
+func (c *Client) GetGatewayByEUI(eui string) (*Gateway, error) {
+	body, err := c.do(fmt.Sprintf("gateways/eui/%s", eui), http.MethodGet, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Gateway *Gateway `json:"gateway"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	if wrapper.Gateway == nil || wrapper.Gateway.EUI == "" {
+		return nil, nil
+	}
+	return wrapper.Gateway, nil
+}
+
+// GetAllGateways gets all gateways that the API key has access to.
+func (c *Client) GetAllGateways() ([]Gateway, error) {
+	body, err := c.do("gateways", http.MethodGet, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Gateways []Gateway `json:"gateways"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Gateways, nil
+}
@@ -0,0 +1,215 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.uber.org/zap"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/log"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
+)
+
+const testAPIKey = "test-api-key"
+
+// server is a local stand-in for the Firefly API.
+type server struct {
+	devices []client.Device
+	// history contains the packets of all devices, and last the last packet of each device.
+	history []client.Packet
+	last    map[string]client.Packet
+	// paginated is false for servers that return all devices on each page.
+	paginated bool
+}
+
+func newServer(t *testing.T) (*server, *httptest.Server) {
+	t.Helper()
+	s := &server{
+		last:      make(map[string]client.Packet),
+		paginated: true,
+	}
+	for i := 0; i < 250; i++ {
+		s.devices = append(s.devices, client.Device{EUI: fmt.Sprintf("00000000000001%02X", i)})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/devices", func(w http.ResponseWriter, r *http.Request) {
+		devs := s.devices
+		if s.paginated {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			start := min(offset, len(devs))
+			devs = devs[start:min(start+limit, len(devs))]
+		}
+		json.NewEncoder(w).Encode(map[string]any{"devices": devs}) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /api/v1/packets", func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit_to_last"))
+		packets := s.history[max(len(s.history)-limit, 0):]
+		json.NewEncoder(w).Encode(map[string]any{"packets": packets}) //nolint:errcheck
+	})
+	mux.HandleFunc("GET /api/v1/devices/eui/{eui}/packets", func(w http.ResponseWriter, r *http.Request) {
+		packets := []client.Packet{}
+		if packet, ok := s.last[r.PathValue("eui")]; ok {
+			packets = append(packets, packet)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"packets": packets}) //nolint:errcheck
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("auth") != testAPIKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func newClient(t *testing.T, url string) *client.Client {
+	t.Helper()
+	cfg := &client.Config{
+		Host:    strings.TrimPrefix(url, "http://"),
+		APIKey:  testAPIKey,
+		UseHTTP: true,
+	}
+	c, err := cfg.NewClient(log.NewContext(context.Background(), zap.NewNop().Sugar()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestGetAllDevices(t *testing.T) {
+	s, ts := newServer(t)
+	c := newClient(t, ts.URL)
+
+	t.Run("Paginated", func(t *testing.T) {
+		a := assertions.New(t)
+		devs, err := c.GetAllDevices()
+		a.So(err, should.BeNil)
+		a.So(devs, should.HaveLength, 250)
+		a.So(devs[249].EUI, should.Equal, "00000000000001F9")
+	})
+
+	t.Run("NotPaginated", func(t *testing.T) {
+		a := assertions.New(t)
+		s.paginated = false
+		defer func() { s.paginated = true }()
+		devs, err := c.GetAllDevices()
+		a.So(err, should.BeNil)
+		a.So(devs, should.HaveLength, 250)
+	})
+
+	t.Run("FullLastPage", func(t *testing.T) {
+		a := assertions.New(t)
+		devs := s.devices
+		s.devices = s.devices[:200]
+		defer func() { s.devices = devs }()
+		got, err := c.GetAllDevices()
+		a.So(err, should.BeNil)
+		a.So(got, should.HaveLength, 200)
+	})
+}
+
+func TestPacketHistory(t *testing.T) {
+	s, ts := newServer(t)
+	c := newClient(t, ts.URL)
+	s.history = []client.Packet{
+		{DeviceEUI: "0000000000000100", FCnt: 10, ReceivedAt: "2026-01-01T10:00:00"},
+		{DeviceEUI: "0000000000000101", FCnt: 5, ReceivedAt: "2026-01-01T10:01:00"},
+		// The frame counter is reset after a join.
+		{DeviceEUI: "0000000000000100", FCnt: 0, ReceivedAt: "2026-01-01T10:02:00"},
+		// Packets of which the time is not known are kept by position.
+		{DeviceEUI: "0000000000000102", FCnt: 8},
+		{DeviceEUI: "0000000000000102", FCnt: 7},
+		// Packets that are not in order of time.
+		{DeviceEUI: "0000000000000103", FCnt: 3, ReceivedAt: "2026-01-01T10:04:00.5Z"},
+		{DeviceEUI: "0000000000000103", FCnt: 2, ReceivedAt: "2026-01-01T10:03:00.5Z"},
+	}
+	s.last["0000000000000104"] = client.Packet{DeviceEUI: "0000000000000104", FCnt: 42}
+
+	t.Run("History", func(t *testing.T) {
+		a := assertions.New(t)
+		h, err := c.GetPacketHistory(len(s.history))
+		a.So(err, should.BeNil)
+		a.So(h.Len(), should.Equal, 4)
+		for eui, expected := range map[string]uint32{
+			"0000000000000100": 0,
+			"0000000000000101": 5,
+			"0000000000000102": 7,
+			"0000000000000103": 3,
+			// Devices that are not in the history are retrieved separately.
+			"0000000000000104": 42,
+		} {
+			fCnt, ok, err := h.LastFCntUp(eui)
+			a.So(err, should.BeNil)
+			a.So(ok, should.BeTrue)
+			a.So(fCnt, should.Equal, expected)
+		}
+		_, ok, err := h.LastFCntUp("0000000000000105")
+		a.So(err, should.BeNil)
+		a.So(ok, should.BeFalse)
+	})
+
+	t.Run("NoHistory", func(t *testing.T) {
+		a := assertions.New(t)
+		h, err := c.GetPacketHistory(0)
+		a.So(err, should.BeNil)
+		a.So(h.Len(), should.Equal, 0)
+		fCnt, ok, err := h.LastFCntUp("0000000000000104")
+		a.So(err, should.BeNil)
+		a.So(ok, should.BeTrue)
+		a.So(fCnt, should.Equal, uint32(42))
+		_, ok, err = h.LastFCntUp("0000000000000100")
+		a.So(err, should.BeNil)
+		a.So(ok, should.BeFalse)
+	})
+}
+
+func TestGetGatewayByEUI(t *testing.T) {
+	a := assertions.New(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/gateways/eui/0102030405060708":
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"gateway": client.Gateway{EUI: "0102030405060708", Name: "gateway"},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{}) //nolint:errcheck
+		}
+	}))
+	t.Cleanup(ts.Close)
+	c := newClient(t, ts.URL)
+
+	gtw, err := c.GetGatewayByEUI("0102030405060708")
+	a.So(err, should.BeNil)
+	a.So(gtw, should.Resemble, &client.Gateway{EUI: "0102030405060708", Name: "gateway"})
+
+	// Responses without a gateway return nil.
+	gtw, err = c.GetGatewayByEUI("0102030405060709")
+	a.So(err, should.BeNil)
+	a.So(gtw, should.BeNil)
+}
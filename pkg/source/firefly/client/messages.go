@@ -42,7 +42,7 @@ type Device struct {
 	OrganizationID        int       `json:"organization_id,omitempty"`
 	OverrideLocation      bool      `json:"override_location,omitempty"`
 	Region                string    `json:"region,omitempty"`
-	Rx2DataRate           int       `json:"rx2_data_rate,omitempty"`
+	Rx2DataRate           *int      `json:"rx2_data_rate,omitempty"`
 	SkipFCntCheck         bool      `json:"skip_fcnt_check,omitempty"`
 	Tags                  []string  `json:"tags,omitempty"`
 	UpdatedAt             string    `json:"updated_at,omitempty"`
@@ -80,7 +80,34 @@ func (d Device) WithIncrementedKeys() Device {
 	return ret
 }
 
+// DeviceClass is a Firefly device class. Device classes define the LoRaWAN settings of devices.
+// The Rx1 delay is in seconds, and the Rx2 frequency is in MHz.
+type DeviceClass struct {
+	ClassB         bool     `json:"class_b,omitempty"`
+	ClassC         bool     `json:"class_c,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	ID             int      `json:"id,omitempty"`
+	LoRaWANVersion string   `json:"lorawan_version,omitempty"`
+	Name           string   `json:"name,omitempty"`
+	Rx1Delay       int      `json:"rx1_delay,omitempty"`
+	Rx2DataRate    *int     `json:"rx2_data_rate,omitempty"`
+	Rx2Frequency   *float64 `json:"rx2_frequency,omitempty"`
+}
+
+// Gateway is a Firefly gateway.
+type Gateway struct {
+	Description    string    `json:"description,omitempty"`
+	EUI            string    `json:"eui,omitempty"`
+	ID             int       `json:"id,omitempty"`
+	Location       *Location `json:"location,omitempty"`
+	Name           string    `json:"name,omitempty"`
+	OrganizationID int       `json:"organization_id,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+}
+
 // Packet is a LoRaWAN uplink packet.
 type Packet struct {
-	FCnt int `json:"fcnt"`
+	DeviceEUI  string `json:"device_eui,omitempty"`
+	FCnt       int    `json:"fcnt"`
+	ReceivedAt string `json:"received_at,omitempty"`
 }
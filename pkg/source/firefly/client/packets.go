@@ -0,0 +1,83 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"strings"
+	"time"
+)
+
+// receivedAtLayouts are the layouts of the time at which packets are received.
+var receivedAtLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"}
+
+// receivedAt returns the time at which the packet is received, or the zero time if it is not known.
+func (p Packet) receivedAt() time.Time {
+	for _, layout := range receivedAtLayouts {
+		if t, err := time.Parse(layout, p.ReceivedAt); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// PacketHistory contains the last packet of devices by EUI.
+type PacketHistory struct {
+	c       *Client
+	packets map[string]Packet
+}
+
+// GetPacketHistory gets the last packets of all devices that the API key has access to. The limit is the
+// number of packets that are retrieved. If the limit is 0, no packets are retrieved.
+func (c *Client) GetPacketHistory(limit int) (*PacketHistory, error) {
+	h := &PacketHistory{
+		c:       c,
+		packets: make(map[string]Packet),
+	}
+	if limit == 0 {
+		return h, nil
+	}
+	packets, err := c.GetPackets(limit)
+	if err != nil {
+		return nil, err
+	}
+	// The most recent packet of each device is kept. Packets of which the time is not known are
+	// considered more recent than the packets before them in the response.
+	for _, packet := range packets {
+		eui := strings.ToUpper(packet.DeviceEUI)
+		if last, ok := h.packets[eui]; ok && packet.receivedAt().Before(last.receivedAt()) {
+			continue
+		}
+		h.packets[eui] = packet
+	}
+	return h, nil
+}
+
+// Len returns the number of devices in the packet history.
+func (h *PacketHistory) Len() int {
+	return len(h.packets)
+}
+
+// LastFCntUp returns the frame counter of the last uplink of the device, and false if the device
+// has not sent any uplinks. The last packet of devices that are not in the history is retrieved separately.
+func (h *PacketHistory) LastFCntUp(eui string) (uint32, bool, error) {
+	if packet, ok := h.packets[strings.ToUpper(eui)]; ok {
+		return uint32(packet.FCnt), true, nil
+	}
+	packet, err := h.c.GetLastPacket(eui)
+	if err != nil || packet == nil {
+		return 0, false, err
+	}
+	return uint32(packet.FCnt), true, nil
+}
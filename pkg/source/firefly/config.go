@@ -20,13 +20,18 @@ import (
 
 	"github.com/spf13/pflag"
 
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
 	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
 	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
 )
 
+// defaultPacketHistorySize is the default number of packets that are retrieved at once.
+const defaultPacketHistorySize = 10000
+
 type Config struct {
 	client.Config
 	src source.Config
@@ -38,6 +43,15 @@ type Config struct {
 	invalidateKeys  bool
 	all             bool
 
+	organizationID   string
+	tags             []string
+	appIDMappingPath string
+	// appIDs maps organizations and tags to application IDs.
+	appIDs map[string]string
+
+	fCntGap           uint
+	packetHistorySize uint
+
 	derivedMacVersion ttnpb.MACVersion
 	derivedPhyVersion ttnpb.PHYVersion
 
@@ -70,11 +84,14 @@ func NewConfig() *Config {
 	config.flags.StringVar(&config.frequencyPlanID,
 		"frequency-plan-id",
 		os.Getenv("FREQUENCY_PLAN_ID"),
-		"Frequency Plan ID for the exported devices")
+		`Frequency Plan ID for the exported gateways, and for devices of which the region is not known.
+The frequency plan of devices is derived from the region of the device.
+For regions with sub-bands, this frequency plan is used if it is of the same region`)
 	config.flags.StringVar(&config.macVersion,
 		"mac-version",
 		os.Getenv("MAC_VERSION"),
-		`LoRaWAN MAC version for the exported devices.
+		`LoRaWAN MAC version for devices without a device class, or of which the device class has no LoRaWAN version.
+The MAC version of devices is derived from their device class.
 Supported options are 1.0.0, 1.0.1, 1.0.2a, 1.0.2b, 1.0.3, 1.1.0a, 1.1.0b`)
 	config.flags.StringVar(&config.appID,
 		"app-id",
@@ -97,6 +114,31 @@ where the devices are exported but they are still valid on the firefly server
 		"all",
 		os.Getenv("EXPORT_ALL") == "true",
 		"Export all devices that the API key has access to. This is only used by the application command")
+	config.flags.StringVar(&config.organizationID,
+		"organization-id",
+		os.Getenv("FIREFLY_ORGANIZATION_ID"),
+		"(optional) Only export devices of this organization ID")
+	config.flags.StringSliceVar(&config.tags,
+		"tag",
+		nil,
+		"(optional) Only export devices with these tags. Can be repeated")
+	config.flags.StringVar(&config.appIDMappingPath,
+		"app-id-mapping-file",
+		os.Getenv("FIREFLY_APP_ID_MAPPING_FILE"),
+		`(optional) Path to a file that maps devices to application IDs, one mapping per line.
+Devices are mapped by tag:<tag> or organization:<id>, in this order.
+Devices that are not mapped use the application ID set with --app-id`)
+	config.flags.UintVar(&config.fCntGap,
+		"fcnt-gap",
+		util.EnvUint("FIREFLY_FCNT_GAP", 0),
+		`Gap that is added to the downlink frame counter of the exported sessions.
+Use a gap to prevent reusing frame counters of downlinks that are sent by Firefly after the export`)
+	config.flags.UintVar(&config.packetHistorySize,
+		"packet-history-size",
+		util.EnvUint("FIREFLY_PACKET_HISTORY_SIZE", defaultPacketHistorySize),
+		`Number of packets of all devices that are retrieved at once to determine the uplink frame counters.
+This is only used by the application command. Set to 0 to retrieve the last packet of each device separately`)
+	config.flags.AddFlagSet(config.HTTP.Flags("FIREFLY_"))
 	return config
 }
 
@@ -107,15 +149,9 @@ func (c *Config) Initialize(src source.Config) error {
 	if apiKey := os.Getenv("FIREFLY_API_KEY"); apiKey != "" && c.APIKey == "" {
 		c.APIKey = apiKey
 	}
-	if c.appID == "" {
-		return errNoAppID.New()
-	}
-	if c.frequencyPlanID == "" {
+	if c.frequencyPlanID == "" && src.Entity != source.EntityEndDevices {
 		return errNoFrequencyPlanID.New()
 	}
-	if c.joinEUI == "" {
-		return errNoJoinEUI.New()
-	}
 	if c.Host == "" {
 		return errNoHost.New()
 	}
@@ -123,30 +159,10 @@ func (c *Config) Initialize(src source.Config) error {
 		return errNoAPIKey.New()
 	}
 
-	switch c.macVersion {
-	case "1.0.0":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_0
-		c.derivedPhyVersion = ttnpb.PHYVersion_TS001_V1_0
-	case "1.0.1":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_0_1
-		c.derivedPhyVersion = ttnpb.PHYVersion_TS001_V1_0_1
-	case "1.0.2a":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_0_2
-		c.derivedPhyVersion = ttnpb.PHYVersion_RP001_V1_0_2
-	case "1.0.2b":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_0_2
-		c.derivedPhyVersion = ttnpb.PHYVersion_RP001_V1_0_2_REV_B
-	case "1.0.3":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_0_3
-		c.derivedPhyVersion = ttnpb.PHYVersion_RP001_V1_0_3_REV_A
-	case "1.1.0a":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_1
-		c.derivedPhyVersion = ttnpb.PHYVersion_RP001_V1_1_REV_A
-	case "1.1.0b":
-		c.derivedMacVersion = ttnpb.MACVersion_MAC_V1_1
-		c.derivedPhyVersion = ttnpb.PHYVersion_RP001_V1_1_REV_B
-	default:
-		return errInvalidMACVersion.WithAttributes("mac_version", c.macVersion)
+	if src.Entity == source.EntityEndDevices {
+		if err := c.initializeDevices(); err != nil {
+			return err
+		}
 	}
 
 	fpFetcher, err := fetch.FromHTTP(http.DefaultClient, src.FrequencyPlansURL)
@@ -158,6 +174,30 @@ func (c *Config) Initialize(src source.Config) error {
 	return nil
 }
 
+// initializeDevices validates the configuration that is only used for exporting end devices.
+func (c *Config) initializeDevices() error {
+	if c.appIDMappingPath != "" {
+		var err error
+		if c.appIDs, err = util.ReadMappingFile(c.appIDMappingPath); err != nil {
+			return err
+		}
+	}
+	if c.appID == "" && len(c.appIDs) == 0 {
+		return errNoAppID.New()
+	}
+	if c.joinEUI == "" {
+		return errNoJoinEUI.New()
+	}
+
+	if c.macVersion != "" {
+		var err error
+		if c.derivedMacVersion, c.derivedPhyVersion, err = session.ParseMACVersion(c.macVersion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Flags returns the flags for the configuration.
 func (c *Config) Flags() *pflag.FlagSet {
 	return c.flags
@@ -0,0 +1,135 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firefly
+
+import (
+	"math"
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
+)
+
+// regionFrequencyPlans maps Firefly regions to frequency plan IDs.
+var regionFrequencyPlans = map[string]string{
+	"EU868": "EU_863_870",
+	"US915": "US_902_928_FSB_2",
+	"AU915": "AU_915_928_FSB_2",
+	"AS923": "AS_920_923",
+	"KR920": "KR_920_923",
+	"IN865": "IN_865_867",
+	"CN470": "CN_470_510_FSB_11",
+}
+
+// subBandRegions contains the frequency plan ID prefix of Firefly regions with sub-bands.
+var subBandRegions = map[string]string{
+	"US915": "US_902_928_",
+	"AU915": "AU_915_928_",
+	"CN470": "CN_470_510_",
+}
+
+// deviceClassCache contains the device classes by ID.
+type deviceClassCache struct {
+	classes map[int]*client.DeviceClass
+}
+
+// getDeviceClass returns the device class, or nil if the device class does not exist.
+// The device classes are retrieved once.
+func (s Source) getDeviceClass(id int) (*client.DeviceClass, error) {
+	if s.deviceClasses.classes == nil {
+		classes, err := s.GetAllDeviceClasses()
+		if err != nil {
+			return nil, err
+		}
+		s.deviceClasses.classes = make(map[int]*client.DeviceClass, len(classes))
+		for i := range classes {
+			s.deviceClasses.classes[classes[i].ID] = &classes[i]
+		}
+	}
+	return s.deviceClasses.classes[id], nil
+}
+
+// setDeviceClass sets the frequency plan, LoRaWAN version, device class and Rx settings of the device
+// from the region and device class of the Firefly device. The configured frequency plan and MAC version
+// are used if they can not be derived.
+func (s Source) setDeviceClass(dev *ttnpb.EndDevice, ffdev *client.Device) error {
+	dev.FrequencyPlanId = s.frequencyPlanID
+	if region := strings.ToUpper(strings.NewReplacer("_", "", "-", "").Replace(ffdev.Region)); region != "" {
+		if fpID, ok := regionFrequencyPlans[region]; ok {
+			// The sub-band is not known, so the configured frequency plan is used if it is of the region.
+			if prefix, ok := subBandRegions[region]; ok {
+				if strings.HasPrefix(s.frequencyPlanID, prefix) {
+					fpID = s.frequencyPlanID
+				} else {
+					s.src.Logger.Warnw("Region has sub-bands, using the default frequency plan",
+						"device_eui", ffdev.EUI,
+						"region", ffdev.Region,
+						"frequency_plan_id", fpID,
+					)
+				}
+			}
+			dev.FrequencyPlanId = fpID
+		} else {
+			s.src.Logger.Warnw("Unknown region, using the configured frequency plan",
+				"device_eui", ffdev.EUI,
+				"region", ffdev.Region,
+				"frequency_plan_id", s.frequencyPlanID,
+			)
+		}
+	}
+	if dev.FrequencyPlanId == "" {
+		return errNoDeviceFrequencyPlanID.WithAttributes("eui", ffdev.EUI, "region", ffdev.Region)
+	}
+
+	dev.LorawanVersion, dev.LorawanPhyVersion = s.derivedMacVersion, s.derivedPhyVersion
+	dev.SupportsClassC = ffdev.ClassC
+	if ffdev.Rx2DataRate != nil {
+		dev.MacSettings.DesiredRx2DataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(*ffdev.Rx2DataRate)}
+	}
+
+	class, err := s.getDeviceClass(ffdev.DeviceClassID)
+	if err != nil {
+		return err
+	}
+	if class == nil {
+		if ffdev.DeviceClassID != 0 {
+			s.src.Logger.Warnw("Device class not found", "device_eui", ffdev.EUI, "device_class_id", ffdev.DeviceClassID)
+		}
+	} else {
+		if class.LoRaWANVersion != "" {
+			if dev.LorawanVersion, dev.LorawanPhyVersion, err = session.ParseMACVersion(class.LoRaWANVersion); err != nil {
+				return err
+			}
+		}
+		dev.SupportsClassB = class.ClassB
+		dev.SupportsClassC = dev.SupportsClassC || class.ClassC
+		if class.Rx1Delay > 0 {
+			dev.MacSettings.Rx1Delay = &ttnpb.RxDelayValue{Value: ttnpb.RxDelay(class.Rx1Delay)}
+		}
+		// The Rx2 data rate of the device takes precedence over the device class.
+		if class.Rx2DataRate != nil && ffdev.Rx2DataRate == nil {
+			dev.MacSettings.DesiredRx2DataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(*class.Rx2DataRate)}
+		}
+		if class.Rx2Frequency != nil {
+			dev.MacSettings.DesiredRx2Frequency = &ttnpb.FrequencyValue{Value: uint64(math.Round(*class.Rx2Frequency * 1e6))}
+		}
+	}
+	if dev.LorawanVersion == ttnpb.MACVersion_MAC_UNKNOWN {
+		return errNoDeviceMACVersion.WithAttributes("eui", ffdev.EUI)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firefly
+
+import (
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
+)
+
+// packetCache contains the packet history of all devices.
+type packetCache struct {
+	history *client.PacketHistory
+}
+
+// loadPackets retrieves the last packets of all devices at once, so that the packets of each device
+// do not have to be retrieved separately.
+func (s Source) loadPackets() error {
+	if s.packets.history != nil {
+		return nil
+	}
+	history, err := s.GetPacketHistory(int(s.packetHistorySize))
+	if err != nil {
+		return err
+	}
+	s.packets.history = history
+	s.src.Logger.Debugw("Loaded packet history", "devices", history.Len())
+	return nil
+}
+
+// lastFCntUp returns the frame counter of the last uplink of the device, and false if the device
+// has not sent any uplinks. Devices that are not in the loaded packet history are retrieved separately.
+func (s Source) lastFCntUp(eui string) (uint32, bool, error) {
+	if s.packets.history == nil {
+		// The packet history is only loaded when exporting applications, so the last packet of each
+		// device is retrieved separately.
+		var err error
+		if s.packets.history, err = s.GetPacketHistory(0); err != nil {
+			return 0, false, err
+		}
+	}
+	return s.packets.history.LastFCntUp(eui)
+}
+
+// setFrameCounters sets the frame counters of the session. The uplink frame counter is the frame counter
+// of the last uplink, or 0 if the device has not sent any uplinks. Firefly stores a single downlink frame
+// counter, which is incremented by the configured gap, so that downlink frame counters that are used
+// after the export are not used again.
+func (s Source) setFrameCounters(dev *ttnpb.EndDevice, ffdev *client.Device) error {
+	fCntUp, ok, err := s.lastFCntUp(ffdev.EUI)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.src.Logger.Debugw("Device has not sent any uplinks, using uplink frame counter 0", "device_eui", ffdev.EUI)
+	}
+	dev.Session.LastFCntUp = fCntUp
+
+	fCntDown := uint32(ffdev.FrameCounter) + uint32(s.fCntGap)
+	// LoRaWAN 1.0.x devices use the network downlink frame counter for all downlinks.
+	dev.Session.LastNFCntDown = fCntDown
+	if dev.LorawanVersion.Compare(ttnpb.MACVersion_MAC_V1_1) >= 0 {
+		dev.Session.LastAFCntDown = fCntDown
+	}
+	return nil
+}
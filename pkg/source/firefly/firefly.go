@@ -25,8 +25,15 @@ var (
 	errNoAppID           = errors.DefineInvalidArgument("no_app_id", "no app id")
 	errNoJoinEUI         = errors.DefineInvalidArgument("no_join_eui", "no join eui")
 	errNoDeviceFound     = errors.DefineInvalidArgument("no_device_found", "no device with eui `{eui}` found")
+	errNoGatewayFound    = errors.DefineInvalidArgument("no_gateway_found", "no gateway with eui `{eui}` found")
 	errNoFrequencyPlanID = errors.DefineInvalidArgument("no_frequency_plan_id", "no frequency plan ID")
-	errInvalidMACVersion = errors.DefineInvalidArgument("invalid_mac_version", "invalid MAC version `{mac_version}`")
+
+	errNoDeviceFrequencyPlanID = errors.DefineInvalidArgument(
+		"no_device_frequency_plan_id",
+		"no frequency plan ID for device `{eui}` in region `{region}`",
+	)
+	errNoDeviceMACVersion = errors.DefineInvalidArgument("no_device_mac_version", "no MAC version for device `{eui}`")
+	errNoDeviceAppID      = errors.DefineInvalidArgument("no_device_app_id", "no application ID for device `{eui}`")
 )
 
 func init() {
@@ -0,0 +1,76 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firefly
+
+import (
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+)
+
+// ExportGateway implements the source.GatewaySource interface.
+func (s Source) ExportGateway(gtwEUIString string) (*ttnpb.Gateway, error) {
+	ffgtw, err := s.GetGatewayByEUI(gtwEUIString)
+	if err != nil {
+		return nil, err
+	}
+	if ffgtw == nil {
+		return nil, errNoGatewayFound.WithAttributes("eui", gtwEUIString)
+	}
+
+	var gtwEUI types.EUI64
+	if err := gtwEUI.UnmarshalText([]byte(gtwEUIString)); err != nil {
+		return nil, err
+	}
+	gtw := &ttnpb.Gateway{
+		Ids: &ttnpb.GatewayIdentifiers{
+			GatewayId: "eui-" + strings.ToLower(gtwEUI.String()),
+			Eui:       gtwEUI.Bytes(),
+		},
+		Name:             ffgtw.Name,
+		Description:      ffgtw.Description,
+		FrequencyPlanId:  s.frequencyPlanID,
+		FrequencyPlanIds: []string{s.frequencyPlanID},
+		EnforceDutyCycle: true,
+	}
+	if ffgtw.Location != nil {
+		gtw.Antennas = []*ttnpb.GatewayAntenna{{
+			Location: &ttnpb.Location{
+				Latitude:  ffgtw.Location.Latitude,
+				Longitude: ffgtw.Location.Longitude,
+				Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+			},
+		}}
+		s.src.Logger.Debugw("Set gateway location", "location", gtw.Antennas[0].Location)
+	}
+	return gtw, nil
+}
+
+// RangeGateways implements the source.GatewaySource interface.
+func (s Source) RangeGateways(f func(source.GatewaySource, string) error) error {
+	gtws, err := s.GetAllGateways()
+	if err != nil {
+		return err
+	}
+	for _, g := range gtws {
+		if err := f(s, g.EUI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
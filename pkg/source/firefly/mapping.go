@@ -0,0 +1,104 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firefly
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+// Prefixes of the keys in the application ID mapping file.
+const (
+	tagMappingPrefix          = "tag:"
+	organizationMappingPrefix = "organization:"
+)
+
+// applicationIDs returns the application IDs of the mapping file and the default application ID.
+func (c *Config) applicationIDs() []string {
+	seen := make(map[string]bool)
+	if c.appID != "" {
+		seen[c.appID] = true
+	}
+	for _, appID := range c.appIDs {
+		seen[appID] = true
+	}
+	appIDs := make([]string, 0, len(seen))
+	for appID := range seen {
+		appIDs = append(appIDs, appID)
+	}
+	sort.Strings(appIDs)
+	return appIDs
+}
+
+// applicationID returns the application ID of the device. Devices are mapped by tags and organization,
+// in this order. Devices that are not mapped use the configured application ID.
+// An empty string is returned if the device is not mapped and no application ID is configured.
+func (c *Config) applicationID(dev client.Device) string {
+	tags := append([]string(nil), dev.Tags...)
+	sort.Strings(tags)
+	for _, tag := range tags {
+		if appID, ok := c.appIDs[tagMappingPrefix+tag]; ok {
+			return appID
+		}
+	}
+	if appID, ok := c.appIDs[organizationMappingPrefix+strconv.Itoa(dev.OrganizationID)]; ok {
+		return appID
+	}
+	return c.appID
+}
+
+// matchesDevice returns true if the device belongs to the configured organization and has all configured tags.
+func (c *Config) matchesDevice(dev client.Device) bool {
+	if c.organizationID != "" && c.organizationID != strconv.Itoa(dev.OrganizationID) {
+		return false
+	}
+	for _, tag := range c.tags {
+		found := false
+		for _, devTag := range dev.Tags {
+			if devTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// tagAttributes returns the tags of the device as attributes. Tags of the form key:value or key=value
+// are split into the attribute key and value, other tags are set to true. Tags that are not valid
+// attributes are skipped with a warning.
+func (s Source) tagAttributes(devEUI string, tags []string) map[string]string {
+	var attributes util.Attributes
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			key, value, ok = strings.Cut(tag, "=")
+		}
+		if !ok {
+			value = "true"
+		}
+		if err := attributes.Set(key, value); err != nil {
+			s.src.Logger.Warnw("Skip tag", "device_eui", devEUI, "tag", tag, "error", err)
+		}
+	}
+	return attributes.Map()
+}
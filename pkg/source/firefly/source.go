@@ -19,21 +19,27 @@ import (
 	"os"
 
 	"github.com/TheThingsNetwork/go-utils/random"
-	"go.thethings.network/lorawan-stack/v3/pkg/networkserver/mac"
 	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
 	"go.thethings.network/lorawan-stack/v3/pkg/types"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.thethings.network/lorawan-stack-migrate/pkg/iterator"
 	"go.thethings.network/lorawan-stack-migrate/pkg/log"
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
 	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 )
 
+// allApplications is the application ID that is used to export all devices.
+const allApplications = "all"
+
 type Source struct {
 	*Config
 	*client.Client
+
+	deviceClasses *deviceClassCache
+	packets       *packetCache
 }
 
 func createNewSource(cfg *Config) source.CreateSource {
@@ -46,8 +52,10 @@ func createNewSource(cfg *Config) source.CreateSource {
 			return nil, err
 		}
 		return Source{
-			Config: cfg,
-			Client: client,
+			Config:        cfg,
+			Client:        client,
+			deviceClasses: &deviceClassCache{},
+			packets:       &packetCache{},
 		}, nil
 	}
 }
@@ -61,11 +69,15 @@ func (s Source) Iterator(isApplication bool) iterator.Iterator {
 		// The Firefly LNS does not group devices by an application.
 		// When the "all" flag is set, we get all devices accessible by the API key.
 		// We use a dummy "all" App ID to fallthrough to the RangeDevices method,
-		// where the appID argument is unused.
+		// where all devices are exported.
 		return iterator.NewListIterator(
-			[]string{"all"},
+			[]string{allApplications},
 		)
 	}
+	if len(s.appIDs) > 0 {
+		// Devices are mapped to applications by organization and tags.
+		return iterator.NewListIterator(s.applicationIDs())
+	}
 	return iterator.NewNoopIterator()
 }
 
@@ -88,22 +100,24 @@ func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
 	if err := joinEUI.UnmarshalText([]byte(s.joinEUI)); err != nil {
 		return nil, err
 	}
+	appID := s.applicationID(*ffdev)
+	if appID == "" {
+		return nil, errNoDeviceAppID.WithAttributes("eui", devEUIString)
+	}
 	v3dev := &ttnpb.EndDevice{
-		Name:            ffdev.Name,
-		Description:     ffdev.Description,
-		FrequencyPlanId: s.frequencyPlanID,
+		Name:        ffdev.Name,
+		Description: ffdev.Description,
+		Attributes:  s.tagAttributes(devEUIString, ffdev.Tags),
 		Ids: &ttnpb.EndDeviceIdentifiers{
-			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: s.appID},
+			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: appID},
 			DevEui:         devEUI.Bytes(),
 			JoinEui:        joinEUI.Bytes(),
 		},
-		MacSettings: &ttnpb.MACSettings{
-			DesiredRx2DataRateIndex: &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(ffdev.Rx2DataRate)},
-		},
-		SupportsClassC:    ffdev.ClassC,
-		SupportsJoin:      ffdev.OTAA,
-		LorawanVersion:    s.derivedMacVersion,
-		LorawanPhyVersion: s.derivedPhyVersion,
+		MacSettings:  &ttnpb.MACSettings{},
+		SupportsJoin: ffdev.OTAA,
+	}
+	if err := s.setDeviceClass(v3dev, ffdev); err != nil {
+		return nil, err
 	}
 	if ffdev.Location != nil {
 		v3dev.Locations = map[string]*ttnpb.Location{
@@ -156,21 +170,14 @@ func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
 			}
 		}
 
-		// Set FrameCounters
-		packet, err := s.GetLastPacket(devEUIString)
-		if err != nil {
+		if err := s.setFrameCounters(v3dev, ffdev); err != nil {
 			return nil, err
 		}
-		v3dev.Session.LastFCntUp = uint32(packet.FCnt)
-		v3dev.Session.LastAFCntDown = uint32(ffdev.FrameCounter)
-		v3dev.Session.LastNFCntDown = uint32(ffdev.FrameCounter)
 
 		// Create a MACState.
-		if v3dev.MacState, err = mac.NewState(v3dev, s.fpStore, &ttnpb.MACSettings{}); err != nil {
+		if v3dev.MacState, err = session.NewMACState(v3dev, s.fpStore); err != nil {
 			return nil, err
 		}
-		v3dev.MacState.CurrentParameters = v3dev.MacState.DesiredParameters
-		v3dev.MacState.CurrentParameters.Rx1Delay = ttnpb.RxDelay_RX_DELAY_1
 	}
 
 	if s.invalidateKeys {
@@ -188,7 +195,9 @@ func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
 }
 
 // RangeDevices implements the source.Source interface.
-func (s Source) RangeDevices(_ string, f func(source.Source, string) error) error {
+// Devices are filtered by organization and tags. If devices are mapped to applications, only the devices
+// of the application are exported, unless all devices are exported.
+func (s Source) RangeDevices(appID string, f func(source.Source, string) error) error {
 	var (
 		devs []client.Device
 		err  error
@@ -198,7 +207,21 @@ func (s Source) RangeDevices(_ string, f func(source.Source, string) error) erro
 	if err != nil {
 		return err
 	}
+	if err := s.loadPackets(); err != nil {
+		return err
+	}
 	for _, d := range devs {
+		if !s.matchesDevice(d) {
+			continue
+		}
+		switch devAppID := s.applicationID(d); {
+		case devAppID == "":
+			s.src.Logger.Warnw("Skip device that is not mapped to an application", "device_eui", d.EUI)
+			continue
+		case len(s.appIDs) > 0 && appID != allApplications && devAppID != appID:
+			s.src.Logger.Debugw("Skip device of other application", "device_eui", d.EUI, "app_id", devAppID)
+			continue
+		}
 		if err := f(s, d.EUI); err != nil {
 			return err
 		}
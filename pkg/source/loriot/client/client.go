@@ -0,0 +1,215 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client provides a client for the network REST API of LORIOT Network Server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/httpclient"
+	"go.thethings.network/lorawan-stack-migrate/pkg/log"
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+)
+
+// basePath is the path of the LORIOT network API.
+const basePath = "/1/nwk/"
+
+// pageSize is the number of items that are requested per page.
+const pageSize = 100
+
+// Config is the LORIOT client configuration.
+type Config struct {
+	URL    string
+	APIKey string
+
+	HTTP httpclient.Config
+}
+
+// Client is a LORIOT client.
+type Client struct {
+	*Config
+	*http.Client
+	ctx context.Context
+
+	baseURL *url.URL
+}
+
+var (
+	errInvalidURL           = errors.DefineInvalidArgument("invalid_url", "invalid URL `{url}`")
+	errResourceNotFound     = errors.DefineNotFound("resource_not_found", "resource `{resource}` not found")
+	errUnauthenticated      = errors.DefineUnauthenticated("unauthenticated", "invalid LORIOT API key")
+	errPermissionDenied     = errors.DefinePermissionDenied("permission_denied", "permission denied for resource `{resource}`")
+	errInvalidKey           = errors.DefineInvalidArgument("invalid_key", "invalid key")
+	errServer               = errors.Define("server", "server error with code `{code}`")
+	errUnexpectedStatusCode = errors.Define("unexpected_status_code", "unexpected status code `{code}`")
+)
+
+// NewClient creates a new LORIOT client.
+func (cfg *Config) NewClient(ctx context.Context) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(cfg.URL, "/") + basePath)
+	if err != nil || baseURL.Host == "" {
+		return nil, errInvalidURL.WithAttributes("url", cfg.URL)
+	}
+	return &Client{
+		Config:  cfg,
+		Client:  cfg.HTTP.New(nil, log.FromContext(ctx)),
+		ctx:     ctx,
+		baseURL: baseURL,
+	}, nil
+}
+
+// do executes an HTTP request.
+func (c *Client) do(method, resource string, params url.Values, body []byte) ([]byte, error) {
+	u := c.baseURL.JoinPath(resource)
+	u.RawQuery = params.Encode()
+
+	logger := log.FromContext(c.ctx).With("url", httpclient.RedactURL(u))
+	logger.Debug("Request resource")
+	req, err := http.NewRequestWithContext(c.ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return nil, httpclient.RedactError(err)
+	}
+	defer res.Body.Close()
+	body, err = io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return body, nil
+	case res.StatusCode == http.StatusUnauthorized:
+		return nil, errUnauthenticated.New()
+	case res.StatusCode == http.StatusForbidden:
+		return nil, errPermissionDenied.WithAttributes("resource", resource)
+	case res.StatusCode == http.StatusNotFound:
+		return nil, errResourceNotFound.WithAttributes("resource", resource)
+	case res.StatusCode >= 500:
+		return nil, errServer.WithAttributes("code", res.StatusCode)
+	default:
+		return nil, errUnexpectedStatusCode.WithAttributes("code", res.StatusCode)
+	}
+}
+
+// list gets all pages of a paginated resource, and calls f with the items of each page.
+// The items are in the field of the response with the key.
+func (c *Client) list(resource, key string, f func(json.RawMessage) (int, error)) error {
+	for n, read := 1, 0; ; n++ {
+		params := url.Values{
+			"page":    []string{strconv.Itoa(n)},
+			"perPage": []string{strconv.Itoa(pageSize)},
+		}
+		body, err := c.do(http.MethodGet, resource, params, nil)
+		if err != nil {
+			return err
+		}
+		var p map[string]json.RawMessage
+		if err := json.Unmarshal(body, &p); err != nil {
+			return err
+		}
+		var total int
+		if raw, ok := p["total"]; ok {
+			if err := json.Unmarshal(raw, &total); err != nil {
+				return err
+			}
+		}
+		count, err := f(p[key])
+		if err != nil {
+			return err
+		}
+		read += count
+		if count == 0 || read >= total {
+			return nil
+		}
+	}
+}
+
+// GetApps gets all applications that the API key has access to.
+func (c *Client) GetApps() ([]App, error) {
+	var apps []App
+	err := c.list("apps", "apps", func(list json.RawMessage) (int, error) {
+		var page []App
+		if err := json.Unmarshal(list, &page); err != nil {
+			return 0, err
+		}
+		apps = append(apps, page...)
+		return len(page), nil
+	})
+	return apps, err
+}
+
+// GetApp gets an application by the hex encoded ID.
+func (c *Client) GetApp(appID string) (*App, error) {
+	body, err := c.do(http.MethodGet, "app/"+appID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var app App
+	if err := json.Unmarshal(body, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
+}
+
+// GetDevices gets all devices of an application.
+func (c *Client) GetDevices(appID string) ([]Device, error) {
+	var devs []Device
+	err := c.list(fmt.Sprintf("app/%s/devices", appID), "devices", func(list json.RawMessage) (int, error) {
+		var page []Device
+		if err := json.Unmarshal(list, &page); err != nil {
+			return 0, err
+		}
+		devs = append(devs, page...)
+		return len(page), nil
+	})
+	return devs, err
+}
+
+// GetDevice gets a device of an application by the DevEUI.
+func (c *Client) GetDevice(appID, devEUI string) (*Device, error) {
+	body, err := c.do(http.MethodGet, fmt.Sprintf("app/%s/device/%s", appID, devEUI), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var dev Device
+	if err := json.Unmarshal(body, &dev); err != nil {
+		return nil, err
+	}
+	return &dev, nil
+}
+
+// UpdateDevice updates the fields of the device that are set.
+func (c *Client) UpdateDevice(appID, devEUI string, dev Device) error {
+	body, err := json.Marshal(dev)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(http.MethodPost, fmt.Sprintf("app/%s/device/%s", appID, devEUI), nil, body)
+	return err
+}
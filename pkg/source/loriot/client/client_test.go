@@ -0,0 +1,195 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.uber.org/zap"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/log"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/loriot/client"
+)
+
+const testAPIKey = "test-api-key"
+
+// server is a local stand-in for the LORIOT network API.
+type server struct {
+	apps    []client.App
+	devices map[string][]client.Device
+	updates map[string]client.Device
+}
+
+func newServer(t *testing.T) (*server, *httptest.Server) {
+	t.Helper()
+	s := &server{
+		apps: []client.App{
+			{
+				ID:      0xBE7A0001,
+				Title:   "Water Meters",
+				Outputs: []client.Output{{Output: "httppush"}},
+			},
+		},
+		devices: make(map[string][]client.Device),
+		updates: make(map[string]client.Device),
+	}
+	for i := 0; i < 150; i++ {
+		s.devices["BE7A0001"] = append(s.devices["BE7A0001"], client.Device{
+			ID:      fmt.Sprintf("00000000000001%02X", i),
+			AppEUI:  "70B3D57ED0000000",
+			AppKey:  "01020304050607080910111213141516",
+			AppSKey: "11111111111111111111111111111111",
+			NwkSKey: "22222222222222222222222222222222",
+			DevAddr: "26010001",
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /1/nwk/apps", func(w http.ResponseWriter, r *http.Request) {
+		writePage(w, r, "apps", s.apps)
+	})
+	mux.HandleFunc("GET /1/nwk/app/{app}", func(w http.ResponseWriter, r *http.Request) {
+		for _, app := range s.apps {
+			if app.HexID() == r.PathValue("app") {
+				json.NewEncoder(w).Encode(app) //nolint:errcheck
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("GET /1/nwk/app/{app}/devices", func(w http.ResponseWriter, r *http.Request) {
+		devs, ok := s.devices[r.PathValue("app")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writePage(w, r, "devices", devs)
+	})
+	mux.HandleFunc("GET /1/nwk/app/{app}/device/{eui}", func(w http.ResponseWriter, r *http.Request) {
+		for _, dev := range s.devices[r.PathValue("app")] {
+			if dev.EUI() == r.PathValue("eui") {
+				json.NewEncoder(w).Encode(dev) //nolint:errcheck
+				return
+			}
+		}
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("POST /1/nwk/app/{app}/device/{eui}", func(w http.ResponseWriter, r *http.Request) {
+		var dev client.Device
+		if err := json.NewDecoder(r.Body).Decode(&dev); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.updates[r.PathValue("eui")] = dev
+		w.WriteHeader(http.StatusNoContent)
+	})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+testAPIKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func writePage[T any](w http.ResponseWriter, r *http.Request, key string, items []T) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("perPage"))
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+		key:       items[start:end],
+		"page":    page,
+		"perPage": perPage,
+		"total":   len(items),
+	})
+}
+
+func newClient(t *testing.T, url, apiKey string) *client.Client {
+	t.Helper()
+	cfg := &client.Config{URL: url, APIKey: apiKey}
+	c, err := cfg.NewClient(log.NewContext(context.Background(), zap.NewNop().Sugar()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestClient(t *testing.T) {
+	s, ts := newServer(t)
+	c := newClient(t, ts.URL, testAPIKey)
+
+	t.Run("GetApps", func(t *testing.T) {
+		a := assertions.New(t)
+		apps, err := c.GetApps()
+		a.So(err, should.BeNil)
+		a.So(apps, should.HaveLength, 1)
+		a.So(apps[0].HexID(), should.Equal, "BE7A0001")
+		a.So(apps[0].Title, should.Equal, "Water Meters")
+		a.So(apps[0].Outputs, should.Resemble, []client.Output{{Output: "httppush"}})
+	})
+
+	t.Run("GetDevices", func(t *testing.T) {
+		a := assertions.New(t)
+		devs, err := c.GetDevices("BE7A0001")
+		a.So(err, should.BeNil)
+		a.So(devs, should.HaveLength, 150)
+		a.So(devs[149].EUI(), should.Equal, "0000000000000195")
+
+		_, err = c.GetDevices("BE7A0002")
+		a.So(err, should.NotBeNil)
+	})
+
+	t.Run("InvalidateKeys", func(t *testing.T) {
+		a := assertions.New(t)
+		dev, err := c.GetDevice("BE7A0001", "0000000000000100")
+		a.So(err, should.BeNil)
+		updated, err := dev.WithIncrementedKeys()
+		a.So(err, should.BeNil)
+		a.So(c.UpdateDevice("BE7A0001", dev.EUI(), updated), should.BeNil)
+		a.So(s.updates["0000000000000100"], should.Resemble, client.Device{
+			AppKey:  "01020304050607080910111213141517",
+			AppSKey: "11111111111111111111111111111112",
+			NwkSKey: "22222222222222222222222222222223",
+		})
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		a := assertions.New(t)
+		_, err := newClient(t, ts.URL, "invalid").GetApps()
+		a.So(err, should.NotBeNil)
+	})
+}
+
+func TestWithIncrementedKeys(t *testing.T) {
+	a := assertions.New(t)
+	dev := client.Device{ID: "0000000000000001", AppKey: "000000000000000000000000000000FF"}
+	updated, err := dev.WithIncrementedKeys()
+	a.So(err, should.BeNil)
+	a.So(updated, should.Resemble, client.Device{AppKey: "00000000000000000000000000000000"})
+
+	_, err = client.Device{AppKey: "invalid"}.WithIncrementedKeys()
+	a.So(err, should.NotBeNil)
+}
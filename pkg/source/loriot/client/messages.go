@@ -0,0 +1,116 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Output is an output of a LORIOT application, that forwards the data of devices to another service.
+type Output struct {
+	Output string         `json:"output"`
+	Setup  map[string]any `json:"osetup,omitempty"`
+}
+
+// App is a LORIOT application. The ID is shown as a hex string by LORIOT.
+type App struct {
+	ID      uint32   `json:"_id"`
+	Title   string   `json:"title,omitempty"`
+	Outputs []Output `json:"outputs,omitempty"`
+}
+
+// HexID returns the ID of the application as it is used in the API.
+func (a App) HexID() string {
+	return fmt.Sprintf("%08X", a.ID)
+}
+
+// Version is the LoRaWAN version of a device.
+type Version struct {
+	Major    int `json:"major"`
+	Minor    int `json:"minor"`
+	Revision int `json:"revision"`
+}
+
+// String returns the version as `major.minor.revision`.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Revision)
+}
+
+// Location is the location of a device. The altitude is in meters.
+type Location struct {
+	Latitude  float64  `json:"lat"`
+	Longitude float64  `json:"lon"`
+	Altitude  *float64 `json:"alt,omitempty"`
+}
+
+// Device is a LORIOT device. The keys are only included if the API key has access to them.
+// SeqNo is the last uplink frame counter, and SeqDn the last downlink frame counter.
+type Device struct {
+	ID          string    `json:"_id,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	DevEUI      string    `json:"deveui,omitempty"`
+	AppEUI      string    `json:"appeui,omitempty"`
+	AppKey      string    `json:"appkey,omitempty"`
+	DevAddr     string    `json:"devaddr,omitempty"`
+	NwkSKey     string    `json:"nwkskey,omitempty"`
+	AppSKey     string    `json:"appskey,omitempty"`
+	SeqNo       *uint32   `json:"seqno,omitempty"`
+	SeqDn       *uint32   `json:"seqdn,omitempty"`
+	DevClass    string    `json:"devclass,omitempty"`
+	LoRaWAN     *Version  `json:"lorawan,omitempty"`
+	Location    *Location `json:"location,omitempty"`
+}
+
+// EUI returns the DevEUI of the device.
+func (d Device) EUI() string {
+	if d.DevEUI != "" {
+		return d.DevEUI
+	}
+	return d.ID
+}
+
+// incrementKey increments the last byte of a hex encoded key.
+func incrementKey(key string) (string, error) {
+	if key == "" {
+		return "", nil
+	}
+	k, err := hex.DecodeString(key)
+	if err != nil || len(k) == 0 {
+		return "", errInvalidKey.WithCause(err)
+	}
+	k[len(k)-1]++
+	return strings.ToUpper(hex.EncodeToString(k)), nil
+}
+
+// WithIncrementedKeys returns a device with only the keys, of which the last byte is incremented.
+func (d Device) WithIncrementedKeys() (Device, error) {
+	var (
+		ret Device
+		err error
+	)
+	if ret.AppKey, err = incrementKey(d.AppKey); err != nil {
+		return Device{}, err
+	}
+	if ret.AppSKey, err = incrementKey(d.AppSKey); err != nil {
+		return Device{}, err
+	}
+	if ret.NwkSKey, err = incrementKey(d.NwkSKey); err != nil {
+		return Device{}, err
+	}
+	return ret, nil
+}
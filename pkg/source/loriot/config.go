@@ -0,0 +1,146 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loriot
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/loriot/client"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
+	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+type Config struct {
+	client.Config
+	src source.Config
+
+	appID           string
+	loriotAppID     string
+	frequencyPlanID string
+	macVersion      string
+	invalidateKeys  bool
+	all             bool
+
+	fCntGap uint
+
+	derivedMacVersion ttnpb.MACVersion
+	derivedPhyVersion ttnpb.PHYVersion
+
+	flags   *pflag.FlagSet
+	fpStore *frequencyplans.Store
+}
+
+// NewConfig returns a new LORIOT configuration.
+func NewConfig() *Config {
+	config := &Config{
+		flags: &pflag.FlagSet{},
+	}
+
+	config.flags.StringVar(&config.URL,
+		"url",
+		os.Getenv("LORIOT_URL"),
+		"URL of the LORIOT server, for example https://eu1.loriot.io")
+	config.flags.StringVar(&config.APIKey,
+		"api-key",
+		"",
+		"Key to access the LORIOT network API")
+	config.flags.StringVar(&config.frequencyPlanID,
+		"frequency-plan-id",
+		os.Getenv("FREQUENCY_PLAN_ID"),
+		"Frequency Plan ID for the exported devices")
+	config.flags.StringVar(&config.macVersion,
+		"mac-version",
+		os.Getenv("LORIOT_MAC_VERSION"),
+		`LoRaWAN MAC version for devices of which the LoRaWAN version is not known.
+Supported options are 1.0.0, 1.0.1, 1.0.2a, 1.0.2b, 1.0.3, 1.1.0a, 1.1.0b`)
+	config.flags.StringVar(&config.appID,
+		"app-id",
+		os.Getenv("APP_ID"),
+		`(optional) Application ID for the exported devices.
+By default, the application ID is derived from the title of the LORIOT application`)
+	config.flags.StringVar(&config.loriotAppID,
+		"loriot-app-id",
+		os.Getenv("LORIOT_APP_ID"),
+		`(optional) Only export devices of this LORIOT application ID, for example BE7A0001.
+This is only used by the device command. By default, the application of each device is looked up`)
+	config.flags.BoolVar(&config.invalidateKeys,
+		"invalidate-keys",
+		os.Getenv("INVALIDATE_KEYS") == "true",
+		`Invalidate the root and session keys of the devices on the LORIOT server.
+This is necessary to prevent both networks from communicating with the same device.
+The last byte of the keys will be incremented by 0x01. This enables an easy rollback if necessary`)
+	config.flags.BoolVar(&config.all,
+		"all",
+		os.Getenv("EXPORT_ALL") == "true",
+		"Export all devices that the API key has access to. This is only used by the application command")
+	config.flags.UintVar(&config.fCntGap,
+		"fcnt-gap",
+		util.EnvUint("LORIOT_FCNT_GAP", 0),
+		`Gap that is added to the downlink frame counter of the exported sessions.
+Use a gap to prevent reusing frame counters of downlinks that are sent by LORIOT after the export`)
+	config.flags.AddFlagSet(config.HTTP.Flags("LORIOT_"))
+	return config
+}
+
+// Initialize the configuration.
+func (c *Config) Initialize(src source.Config) error {
+	c.src = src
+
+	if apiKey := os.Getenv("LORIOT_API_KEY"); apiKey != "" && c.APIKey == "" {
+		c.APIKey = apiKey
+	}
+	if c.URL == "" {
+		return errNoURL.New()
+	}
+	if c.APIKey == "" {
+		return errNoAPIKey.New()
+	}
+	if c.frequencyPlanID == "" {
+		return errNoFrequencyPlanID.New()
+	}
+	c.loriotAppID = strings.ToUpper(c.loriotAppID)
+	if c.macVersion != "" {
+		var err error
+		if c.derivedMacVersion, c.derivedPhyVersion, err = session.ParseMACVersion(c.macVersion); err != nil {
+			return err
+		}
+	}
+	// Source devices are not updated during a dry run.
+	if src.DryRun && c.invalidateKeys {
+		src.Logger.Warn("Cannot invalidate keys of source devices during a dry run.")
+		c.invalidateKeys = false
+	}
+
+	fpFetcher, err := fetch.FromHTTP(http.DefaultClient, src.FrequencyPlansURL)
+	if err != nil {
+		return err
+	}
+	c.fpStore = frequencyplans.NewStore(fpFetcher)
+
+	return nil
+}
+
+// Flags returns the flags for the configuration.
+func (c *Config) Flags() *pflag.FlagSet {
+	return c.flags
+}
@@ -0,0 +1,194 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loriot
+
+import (
+	"math"
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/session"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/loriot/client"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+// derivedApplicationID returns the application ID of the devices of the LORIOT application. This is the
+// configured application ID, or the title of the LORIOT application as identifier.
+func (s Source) derivedApplicationID(app client.App) string {
+	if s.appID != "" {
+		return s.appID
+	}
+	if id := util.Slug(app.Title); id != "" {
+		return id
+	}
+	return "loriot-" + strings.ToLower(app.HexID())
+}
+
+// applicationID returns the application ID of the devices of the LORIOT application. An error is returned
+// if the application ID is derived from the title of another LORIOT application as well.
+func (s Source) applicationID(app client.App) (string, error) {
+	appID := s.derivedApplicationID(app)
+	if s.appID != "" {
+		return appID, nil
+	}
+	// Applications with similar titles may be converted to the same application ID.
+	if other, ok := s.apps.appIDs[appID]; ok && other != app.HexID() {
+		return "", errApplicationIDConflict.WithAttributes(
+			"application_id", appID,
+			"loriot_app_id", app.HexID(),
+			"other_loriot_app_id", other,
+		)
+	}
+	s.apps.appIDs[appID] = app.HexID()
+	return appID, nil
+}
+
+// outputHints are the integrations of The Things Stack that replace the outputs of LORIOT applications.
+var outputHints = map[string]string{
+	"httppush":    "Webhook",
+	"httppush2":   "Webhook",
+	"mqtt":        "MQTT integration or Pub/Sub integration",
+	"websocket":   "MQTT integration",
+	"azureiot":    "Azure IoT Hub integration",
+	"azureiothub": "Azure IoT Hub integration",
+	"awsiot":      "AWS IoT integration",
+}
+
+// logOutputs logs the outputs of the LORIOT application. Outputs are not migrated, but need to be set up
+// as integrations on The Things Stack.
+func (s Source) logOutputs(app client.App) {
+	for _, output := range app.Outputs {
+		hint, ok := outputHints[strings.ToLower(output.Output)]
+		if !ok {
+			hint = "an integration"
+		}
+		s.src.Logger.Warnw("LORIOT application has an output that is not migrated, set up a "+hint,
+			"loriot_app_id", app.HexID(),
+			"app_id", s.derivedApplicationID(app),
+			"output", output.Output,
+		)
+	}
+}
+
+// deviceVersion returns the MAC and PHY version of the device, or the configured MAC version if the
+// LoRaWAN version of the device is not known.
+func (s Source) deviceVersion(dev client.Device) (ttnpb.MACVersion, ttnpb.PHYVersion, error) {
+	if dev.LoRaWAN != nil {
+		if macVersion, phyVersion, err := session.ParseMACVersion(dev.LoRaWAN.String()); err == nil {
+			return macVersion, phyVersion, nil
+		}
+		s.src.Logger.Debugw("Unknown LoRaWAN version", "device_eui", dev.EUI(), "version", dev.LoRaWAN.String())
+	}
+	if s.derivedMacVersion == ttnpb.MACVersion_MAC_UNKNOWN {
+		return ttnpb.MACVersion_MAC_UNKNOWN, ttnpb.PHYVersion_PHY_UNKNOWN,
+			errNoDeviceMACVersion.WithAttributes("eui", dev.EUI())
+	}
+	return s.derivedMacVersion, s.derivedPhyVersion, nil
+}
+
+// endDevice converts the LORIOT device of the application to an end device.
+func (s Source) endDevice(app client.App, dev client.Device) (*ttnpb.EndDevice, error) {
+	var devEUI types.EUI64
+	if err := devEUI.UnmarshalText([]byte(dev.EUI())); err != nil {
+		return nil, err
+	}
+	appID, err := s.applicationID(app)
+	if err != nil {
+		return nil, err
+	}
+	v3dev := &ttnpb.EndDevice{
+		Name:        dev.Title,
+		Description: dev.Description,
+		Ids: &ttnpb.EndDeviceIdentifiers{
+			ApplicationIds: &ttnpb.ApplicationIdentifiers{ApplicationId: appID},
+			DevEui:         devEUI.Bytes(),
+		},
+		FrequencyPlanId: s.frequencyPlanID,
+		MacSettings:     &ttnpb.MACSettings{},
+		SupportsJoin:    dev.AppKey != "",
+	}
+	if v3dev.LorawanVersion, v3dev.LorawanPhyVersion, err = s.deviceVersion(dev); err != nil {
+		return nil, err
+	}
+	switch strings.ToUpper(dev.DevClass) {
+	case "B":
+		v3dev.SupportsClassB = true
+	case "C":
+		v3dev.SupportsClassC = true
+	}
+	if dev.Location != nil {
+		location := &ttnpb.Location{
+			Latitude:  dev.Location.Latitude,
+			Longitude: dev.Location.Longitude,
+			Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+		}
+		if dev.Location.Altitude != nil {
+			location.Altitude = int32(math.Round(*dev.Location.Altitude))
+		}
+		v3dev.Locations = map[string]*ttnpb.Location{"user": location}
+	}
+
+	if v3dev.SupportsJoin {
+		if dev.AppEUI == "" {
+			return nil, errNoDeviceJoinEUI.WithAttributes("eui", dev.EUI())
+		}
+		v3dev.Ids.JoinEui, err = util.UnmarshalTextToBytes(&types.EUI64{}, dev.AppEUI)
+		if err != nil {
+			return nil, err
+		}
+		v3dev.RootKeys = &ttnpb.RootKeys{AppKey: &ttnpb.KeyEnvelope{}}
+		v3dev.RootKeys.AppKey.Key, err = util.UnmarshalTextToBytes(&types.AES128Key{}, dev.AppKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hasSession := dev.DevAddr != "" && dev.NwkSKey != "" && dev.AppSKey != ""
+	if hasSession || !v3dev.SupportsJoin {
+		if v3dev.Session, err = session.New(v3dev, session.Keys{
+			DevAddr:     dev.DevAddr,
+			AppSKey:     dev.AppSKey,
+			FNwkSIntKey: dev.NwkSKey,
+		}); err != nil {
+			return nil, err
+		}
+		s.setFrameCounters(v3dev, dev)
+		if v3dev.MacState, err = session.NewMACState(v3dev, s.fpStore); err != nil {
+			return nil, err
+		}
+	}
+	return v3dev, nil
+}
+
+// setFrameCounters sets the frame counters of the session. LORIOT stores a single downlink frame counter,
+// which is incremented by the configured gap, so that downlink frame counters that are used after the
+// export are not used again.
+func (s Source) setFrameCounters(dev *ttnpb.EndDevice, ldev client.Device) {
+	if ldev.SeqNo != nil {
+		dev.Session.LastFCntUp = *ldev.SeqNo
+	}
+	var fCntDown uint32
+	if ldev.SeqDn != nil {
+		fCntDown = *ldev.SeqDn
+	}
+	fCntDown += uint32(s.fCntGap)
+	// LoRaWAN 1.0.x devices use the network downlink frame counter for all downlinks.
+	dev.Session.LastNFCntDown = fCntDown
+	if dev.LorawanVersion.Compare(ttnpb.MACVersion_MAC_V1_1) >= 0 {
+		dev.Session.LastAFCntDown = fCntDown
+	}
+}
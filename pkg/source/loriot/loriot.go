@@ -0,0 +1,46 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loriot
+
+import (
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+)
+
+var (
+	errNoAPIKey          = errors.DefineInvalidArgument("no_api_key", "no api key")
+	errNoURL             = errors.DefineInvalidArgument("no_url", "no url")
+	errNoFrequencyPlanID = errors.DefineInvalidArgument("no_frequency_plan_id", "no frequency plan ID")
+	errNoDeviceFound     = errors.DefineNotFound("no_device_found", "no device with eui `{eui}` found")
+	errInvalidAppID      = errors.DefineInvalidArgument("invalid_app_id", "invalid LORIOT application ID `{app_id}`")
+
+	errNoDeviceMACVersion    = errors.DefineInvalidArgument("no_device_mac_version", "no MAC version for device `{eui}`")
+	errNoDeviceJoinEUI       = errors.DefineInvalidArgument("no_device_join_eui", "no JoinEUI for device `{eui}`")
+	errApplicationIDConflict = errors.DefineAlreadyExists(
+		"application_id_conflict",
+		"application ID `{application_id}` of LORIOT application `{loriot_app_id}` is already used by LORIOT application `{other_loriot_app_id}`",
+	)
+)
+
+func init() {
+	cfg := NewConfig()
+
+	source.RegisterSource(source.Registration{
+		Name:        "loriot",
+		Description: "Migrate from LORIOT Network Server",
+		FlagSet:     cfg.Flags(),
+		Create:      createNewSource(cfg),
+	})
+}
@@ -0,0 +1,267 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loriot
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+	"go.thethings.network/lorawan-stack/v3/pkg/types"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/iterator"
+	"go.thethings.network/lorawan-stack-migrate/pkg/log"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/loriot/client"
+)
+
+// allApplications is the application ID that is used to export all devices.
+const allApplications = "all"
+
+type Source struct {
+	*Config
+	*client.Client
+
+	apps *appCache
+	// exported contains the devices of which the keys are invalidated after they are exported, by DevEUI.
+	exported map[string]exportedDevice
+}
+
+// exportedDevice is a LORIOT device with the DevEUI as used in the API, and the hex ID of its application.
+type exportedDevice struct {
+	appID string
+	eui   string
+	dev   client.Device
+}
+
+// appCache contains the LORIOT applications by hex ID, and the hex ID of the application of devices by DevEUI.
+// The hex IDs of applications are also stored by the application ID that is derived from the title.
+type appCache struct {
+	apps    map[string]client.App
+	devices map[string]string
+	appIDs  map[string]string
+	// loaded is true if the devices of all applications are in the cache.
+	loaded bool
+}
+
+func createNewSource(cfg *Config) source.CreateSource {
+	return func(ctx context.Context, src source.Config) (source.Source, error) {
+		if err := cfg.Initialize(src); err != nil {
+			return nil, err
+		}
+		c, err := cfg.NewClient(log.NewContext(ctx, src.Logger))
+		if err != nil {
+			return nil, err
+		}
+		return Source{
+			Config: cfg,
+			Client: c,
+			apps: &appCache{
+				apps:    make(map[string]client.App),
+				devices: make(map[string]string),
+				appIDs:  make(map[string]string),
+			},
+			exported: make(map[string]exportedDevice),
+		}, nil
+	}
+}
+
+// normalizeEUI returns the EUI in the format that is used as key of the cache.
+func normalizeEUI(eui string) (string, error) {
+	var devEUI types.EUI64
+	if err := devEUI.UnmarshalText([]byte(eui)); err != nil {
+		return "", err
+	}
+	return devEUI.String(), nil
+}
+
+// getApp returns the LORIOT application by hex ID. Applications are retrieved once.
+func (s Source) getApp(appID string) (client.App, error) {
+	appID = strings.ToUpper(appID)
+	if app, ok := s.apps.apps[appID]; ok {
+		return app, nil
+	}
+	app, err := s.GetApp(appID)
+	if err != nil {
+		return client.App{}, err
+	}
+	s.apps.apps[appID] = *app
+	s.logOutputs(*app)
+	return *app, nil
+}
+
+// getDevices returns the devices of the LORIOT application, and adds them to the cache.
+func (s Source) getDevices(app client.App) ([]client.Device, error) {
+	devs, err := s.GetDevices(app.HexID())
+	if err != nil {
+		return nil, err
+	}
+	for _, dev := range devs {
+		eui, err := normalizeEUI(dev.EUI())
+		if err != nil {
+			return nil, err
+		}
+		s.apps.devices[eui] = app.HexID()
+	}
+	return devs, nil
+}
+
+// loadApps retrieves all applications and their devices, so that the application of devices can be looked up.
+func (s Source) loadApps() error {
+	if s.apps.loaded {
+		return nil
+	}
+	apps, err := s.GetApps()
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		if _, ok := s.apps.apps[app.HexID()]; !ok {
+			s.apps.apps[app.HexID()] = app
+			s.logOutputs(app)
+		}
+		if _, err := s.getDevices(app); err != nil {
+			return err
+		}
+	}
+	s.apps.loaded = true
+	s.src.Logger.Debugw("Loaded applications", "applications", len(apps), "devices", len(s.apps.devices))
+	return nil
+}
+
+// deviceApp returns the LORIOT application of the device.
+func (s Source) deviceApp(eui string) (client.App, error) {
+	if s.loriotAppID != "" {
+		return s.getApp(s.loriotAppID)
+	}
+	appID, ok := s.apps.devices[eui]
+	if !ok {
+		if err := s.loadApps(); err != nil {
+			return client.App{}, err
+		}
+		if appID, ok = s.apps.devices[eui]; !ok {
+			return client.App{}, errNoDeviceFound.WithAttributes("eui", eui)
+		}
+	}
+	return s.getApp(appID)
+}
+
+// Iterator implements source.Source.
+func (s Source) Iterator(isApplication bool) iterator.Iterator {
+	if !isApplication {
+		return iterator.NewReaderIterator(os.Stdin, '\n')
+	}
+	if s.all {
+		return iterator.NewListIterator(
+			[]string{allApplications},
+		)
+	}
+	return iterator.NewNoopIterator()
+}
+
+// ExportDevice implements the source.Source interface.
+func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
+	eui, err := normalizeEUI(devEUIString)
+	if err != nil {
+		return nil, err
+	}
+	app, err := s.deviceApp(eui)
+	if err != nil {
+		return nil, err
+	}
+	dev, err := s.GetDevice(app.HexID(), eui)
+	if err != nil {
+		return nil, err
+	}
+	v3dev, err := s.endDevice(app, *dev)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.invalidateKeys {
+		// The keys are invalidated after the device is exported.
+		s.exported[hex.EncodeToString(v3dev.Ids.DevEui)] = exportedDevice{appID: app.HexID(), eui: eui, dev: *dev}
+	}
+	return v3dev, nil
+}
+
+// DeviceExported implements the source.ExportedDeviceHandler interface.
+// The keys of the LORIOT device are invalidated if --invalidate-keys is set.
+func (s Source) DeviceExported(dev *ttnpb.EndDevice) error {
+	key := hex.EncodeToString(dev.Ids.DevEui)
+	exported, ok := s.exported[key]
+	if !ok {
+		return nil
+	}
+	delete(s.exported, key)
+	s.src.Logger.Debugw("Increment the last byte of the device keys",
+		"device_eui", exported.eui,
+		"loriot_app_id", exported.appID,
+	)
+	// Increment the last byte of the device keys.
+	// This makes it easier to rollback a migration if needed.
+	updated, err := exported.dev.WithIncrementedKeys()
+	if err != nil {
+		return err
+	}
+	return s.UpdateDevice(exported.appID, exported.eui, updated)
+}
+
+// RangeDevices implements the source.Source interface.
+// The application ID is the hex ID of the LORIOT application, for example BE7A0001.
+func (s Source) RangeDevices(appID string, f func(source.Source, string) error) error {
+	var apps []client.App
+	if appID == allApplications {
+		s.src.Logger.Debugw("Get all applications accessible by the API key")
+		all, err := s.GetApps()
+		if err != nil {
+			return err
+		}
+		for _, app := range all {
+			if _, ok := s.apps.apps[app.HexID()]; !ok {
+				s.apps.apps[app.HexID()] = app
+				s.logOutputs(app)
+			}
+		}
+		apps = all
+	} else {
+		if _, err := strconv.ParseUint(appID, 16, 32); err != nil {
+			return errInvalidAppID.WithAttributes("app_id", appID)
+		}
+		app, err := s.getApp(appID)
+		if err != nil {
+			return err
+		}
+		apps = []client.App{app}
+	}
+	for _, app := range apps {
+		devs, err := s.getDevices(app)
+		if err != nil {
+			return err
+		}
+		for _, dev := range devs {
+			if err := f(s, dev.EUI()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close implements the Source interface.
+func (s Source) Close() error { return nil }
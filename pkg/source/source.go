@@ -25,10 +25,25 @@ import (
 	"go.uber.org/zap"
 )
 
+// Entity is the type of entities exported from a source.
+type Entity int
+
+const (
+	// EntityEndDevices exports end devices.
+	EntityEndDevices Entity = iota
+	// EntityGateways exports gateways.
+	EntityGateways
+	// EntityApplicationSettings exports application settings and integrations.
+	EntityApplicationSettings
+)
+
 type Config struct {
 	DryRun, Verbose   bool
 	FrequencyPlansURL string
 
+	// Entity is the type of entities that are exported.
+	Entity Entity
+
 	Logger *zap.SugaredLogger
 
 	source string
@@ -60,6 +75,36 @@ type Source interface {
 	Iterator(isApplication bool) iterator.Iterator
 }
 
+// ExportedDeviceHandler is implemented by sources that act on devices after they are exported, for example
+// to invalidate the keys of the source device.
+type ExportedDeviceHandler interface {
+	// DeviceExported is called after the end device is validated and written to the output.
+	// The end device has the final identifiers.
+	DeviceExported(dev *ttnpb.EndDevice) error
+}
+
+// GatewaySource is a source for gateways.
+type GatewaySource interface {
+	// ExportGateway retrieves a gateway from the source and returns it as a ttnpb.Gateway.
+	ExportGateway(gtwID string) (*ttnpb.Gateway, error)
+	// RangeGateways calls a function for all gateways of the source.
+	RangeGateways(f func(s GatewaySource, gtwID string) error) error
+}
+
+// ApplicationSettings is an application with its link and integrations.
+type ApplicationSettings struct {
+	Application *ttnpb.Application
+	Link        *ttnpb.ApplicationLink
+	Webhooks    []*ttnpb.ApplicationWebhook
+	PubSubs     []*ttnpb.ApplicationPubSub
+}
+
+// ApplicationSettingsSource is a source for application settings.
+type ApplicationSettingsSource interface {
+	// ExportApplicationSettings retrieves an application with its link and integrations from the source.
+	ExportApplicationSettings(appID string) (*ApplicationSettings, error)
+}
+
 // CreateSource is a function that constructs a new Source.
 type CreateSource func(ctx context.Context, rootCfg Config) (Source, error)
 
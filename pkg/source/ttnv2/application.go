@@ -0,0 +1,91 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ttnv2
+
+import (
+	"fmt"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/log"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+)
+
+// uplinkFormatterFormat chains the V2 decoder, converter and validator functions into a single uplink formatter.
+const uplinkFormatterFormat = `%s
+
+%s
+
+%s
+
+function decodeUplink(input) {
+	var decoded = Decoder(input.bytes, input.fPort);
+	if (typeof Converter === "function") {
+		decoded = Converter(decoded, input.fPort);
+	}
+	if (typeof Validator === "function" && !Validator(decoded, input.fPort)) {
+		return { errors: ["validation failed"] };
+	}
+	return { data: decoded };
+}`
+
+// ExportApplicationSettings implements the source.ApplicationSettingsSource interface.
+func (s *Source) ExportApplicationSettings(appID string) (*source.ApplicationSettings, error) {
+	if appID != s.config.appID {
+		return nil, errAppIDMismatch.WithAttributes(
+			"app_id", appID,
+			"configured_app_id", s.config.appID,
+		)
+	}
+	mgr, err := s.client.ManageApplication()
+	if err != nil {
+		return nil, err
+	}
+	format, err := mgr.GetPayloadFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	formatters := &ttnpb.MessagePayloadFormatters{}
+	switch format {
+	case "cayenne", "cayennelpp":
+		formatters.UpFormatter = ttnpb.PayloadFormatter_FORMATTER_CAYENNELPP
+		formatters.DownFormatter = ttnpb.PayloadFormatter_FORMATTER_CAYENNELPP
+	case "custom":
+		decoder, converter, validator, encoder, err := mgr.GetCustomPayloadFunctions()
+		if err != nil {
+			return nil, err
+		}
+		if decoder != "" {
+			formatters.UpFormatter = ttnpb.PayloadFormatter_FORMATTER_JAVASCRIPT
+			formatters.UpFormatterParameter = fmt.Sprintf(uplinkFormatterFormat, decoder, converter, validator)
+		}
+		if encoder != "" {
+			// The Things Stack supports the V2 Encoder(object, port) function signature.
+			formatters.DownFormatter = ttnpb.PayloadFormatter_FORMATTER_JAVASCRIPT
+			formatters.DownFormatterParameter = encoder
+		}
+	}
+
+	log.FromContext(s.ctx).WithField("app_id", appID).Warn("TTN V2 HTTP integrations are not available through the Handler API and are not exported, recreate them as webhooks")
+	return &source.ApplicationSettings{
+		Application: &ttnpb.Application{
+			Ids: &ttnpb.ApplicationIdentifiers{ApplicationId: appID},
+		},
+		Link: &ttnpb.ApplicationLink{
+			DefaultFormatters: formatters,
+		},
+	}, nil
+}
@@ -142,7 +142,7 @@ func (c *Config) Initialize(rootConfig source.Config) error {
 	if c.appAccessKey == "" {
 		return errNoAppAccessKey.New()
 	}
-	if c.frequencyPlanID == "" {
+	if c.frequencyPlanID == "" && rootConfig.Entity != source.EntityApplicationSettings {
 		return errNoFrequencyPlanID.New()
 	}
 
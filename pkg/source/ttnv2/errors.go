@@ -22,4 +22,6 @@ var (
 	errNoAppID           = errors.DefineInvalidArgument("no_app_id", "no app id")
 	errNoAppAccessKey    = errors.DefineInvalidArgument("no_app_access_key", "no app access key")
 	errNoFrequencyPlanID = errors.DefineInvalidArgument("no_frequency_plan_id", "no frequency plan id")
+
+	errAppIDMismatch = errors.DefineInvalidArgument("app_id_mismatch", "application `{app_id}` does not match the configured application `{configured_app_id}`")
 )
@@ -0,0 +1,101 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tts
+
+import (
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/tts/api"
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+var (
+	applicationGetFieldMask = []string{
+		"attributes",
+		"description",
+		"name",
+	}
+	applicationLinkGetFieldMask = []string{
+		"default_formatters",
+		"skip_payload_crypto",
+	}
+	// Timestamps and health status are bound to the source deployment and are not exported.
+	applicationWebhookGetFieldMask = ttnpb.ExcludeFields(
+		ttnpb.ApplicationWebhookFieldPathsTopLevel,
+		"created_at",
+		"health_status",
+		"updated_at",
+	)
+	applicationPubSubGetFieldMask = ttnpb.ExcludeFields(
+		ttnpb.ApplicationPubSubFieldPathsTopLevel,
+		"created_at",
+		"updated_at",
+	)
+)
+
+// ExportApplicationSettings implements the source.ApplicationSettingsSource interface.
+func (s Source) ExportApplicationSettings(appID string) (*source.ApplicationSettings, error) {
+	ids := &ttnpb.ApplicationIdentifiers{ApplicationId: appID}
+
+	is, err := api.Dial(s.ctx, s.config.ServerConfig.IdentityServerGRPCAddress)
+	if err != nil {
+		return nil, err
+	}
+	app, err := ttnpb.NewApplicationRegistryClient(is).Get(s.ctx, &ttnpb.GetApplicationRequest{
+		ApplicationIds: ids,
+		FieldMask:      ttnpb.FieldMask(applicationGetFieldMask...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings := &source.ApplicationSettings{Application: app}
+
+	as, err := api.Dial(s.ctx, s.config.ServerConfig.ApplicationServerGRPCAddress)
+	if err != nil {
+		return nil, err
+	}
+	link, err := ttnpb.NewAsClient(as).GetLink(s.ctx, &ttnpb.GetApplicationLinkRequest{
+		ApplicationIds: ids,
+		FieldMask:      ttnpb.FieldMask(applicationLinkGetFieldMask...),
+	})
+	switch {
+	case err == nil:
+		settings.Link = link
+	case errors.IsNotFound(err):
+		s.config.Logger.With("application_id", appID).Debug("No application link")
+	default:
+		return nil, err
+	}
+
+	webhooks, err := ttnpb.NewApplicationWebhookRegistryClient(as).List(s.ctx, &ttnpb.ListApplicationWebhooksRequest{
+		ApplicationIds: ids,
+		FieldMask:      ttnpb.FieldMask(applicationWebhookGetFieldMask...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings.Webhooks = webhooks.Webhooks
+
+	pubsubs, err := ttnpb.NewApplicationPubSubRegistryClient(as).List(s.ctx, &ttnpb.ListApplicationPubSubsRequest{
+		ApplicationIds: ids,
+		FieldMask:      ttnpb.FieldMask(applicationPubSubGetFieldMask...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	settings.PubSubs = pubsubs.Pubsubs
+
+	return settings, nil
+}
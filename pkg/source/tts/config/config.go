@@ -80,6 +80,10 @@ func New() *Config {
 		"app-api-key",
 		"",
 		"TTS Application Access Key (with 'devices' permissions)")
+	config.flags.StringVar(&config.gatewayAPIKey,
+		"gateway-api-key",
+		"",
+		"TTS User or Organization API Key for exporting gateways (with rights to view and list gateways)")
 
 	config.flags.StringVar(&config.caPath,
 		"ca-file",
@@ -131,6 +135,8 @@ type Config struct {
 	insecure  bool
 	caPath    string
 	appAPIKey string
+	// gatewayAPIKey is a user or organization API key, since application API keys have no gateway rights.
+	gatewayAPIKey string
 
 	NoSession          bool
 	DeleteSourceDevice bool
@@ -145,14 +151,24 @@ func (c *Config) Initialize(rootConfig source.Config) error {
 	if appAPIKey := os.Getenv("TTS_APP_API_KEY"); appAPIKey != "" && c.appAPIKey == "" {
 		c.appAPIKey = appAPIKey
 	}
-	if c.AppID == "" {
+	if gatewayAPIKey := os.Getenv("TTS_GATEWAY_API_KEY"); gatewayAPIKey != "" && c.gatewayAPIKey == "" {
+		c.gatewayAPIKey = gatewayAPIKey
+	}
+	if c.AppID == "" && c.Entity == source.EntityEndDevices {
 		return errNoAppID.New()
 	}
 
-	if c.appAPIKey == "" {
-		return errNoAppAPIKey.New()
+	if c.Entity == source.EntityGateways {
+		if c.gatewayAPIKey == "" {
+			return errNoGatewayAPIKey.New()
+		}
+		api.SetAuth("bearer", c.gatewayAPIKey)
+	} else {
+		if c.appAPIKey == "" {
+			return errNoAppAPIKey.New()
+		}
+		api.SetAuth("bearer", c.appAPIKey)
 	}
-	api.SetAuth("bearer", c.appAPIKey)
 
 	switch {
 	case c.insecure:
@@ -0,0 +1,86 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tts
+
+import (
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/tts/api"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+// gatewayGetFieldMask contains the gateway fields that are exported.
+// Fields that are bound to the source deployment (e.g. server addresses and
+// secrets) are not exported.
+var gatewayGetFieldMask = []string{
+	"antennas",
+	"attributes",
+	"auto_update",
+	"description",
+	"disable_packet_broker_forwarding",
+	"downlink_path_constraint",
+	"enforce_duty_cycle",
+	"frequency_plan_id",
+	"frequency_plan_ids",
+	"location_public",
+	"name",
+	"require_authenticated_connection",
+	"schedule_anytime_delay",
+	"schedule_downlink_late",
+	"status_public",
+	"update_channel",
+	"update_location_from_status",
+	"version_ids",
+}
+
+// ExportGateway implements the source.GatewaySource interface.
+func (s Source) ExportGateway(gtwID string) (*ttnpb.Gateway, error) {
+	is, err := api.Dial(s.ctx, s.config.ServerConfig.IdentityServerGRPCAddress)
+	if err != nil {
+		return nil, err
+	}
+	return ttnpb.NewGatewayRegistryClient(is).Get(s.ctx, &ttnpb.GetGatewayRequest{
+		GatewayIds: &ttnpb.GatewayIdentifiers{GatewayId: gtwID},
+		FieldMask:  ttnpb.FieldMask(gatewayGetFieldMask...),
+	})
+}
+
+// RangeGateways implements the source.GatewaySource interface.
+func (s Source) RangeGateways(f func(source.GatewaySource, string) error) error {
+	is, err := api.Dial(s.ctx, s.config.ServerConfig.IdentityServerGRPCAddress)
+	if err != nil {
+		return err
+	}
+	limit, page, opt, getTotal := withPagination()
+	for {
+		res, err := ttnpb.NewGatewayRegistryClient(is).List(s.ctx, &ttnpb.ListGatewaysRequest{
+			FieldMask: ttnpb.FieldMask("ids.gateway_id"),
+			Limit:     limit,
+			Page:      page,
+		}, opt)
+		if err != nil {
+			return err
+		}
+		for _, g := range res.Gateways {
+			if err := f(s, g.Ids.GatewayId); err != nil {
+				return err
+			}
+		}
+		if total := getTotal(); uint64(page)*uint64(limit) >= total {
+			break
+		}
+		page++
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wanesy
+
+import (
+	"strconv"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/wanesy/client"
+)
+
+// formatInt formats an optional integer as in the CSV export of WMC.
+func formatInt(v *int, base int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(int64(*v), base)
+}
+
+// formatFloat formats an optional floating point number as in the CSV export of WMC.
+func formatFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+// formatBool formats a boolean as in the CSV export of WMC.
+func formatBool(v bool) string {
+	if v {
+		return "True"
+	}
+	return "False"
+}
+
+// deviceFromAPI converts a device of the WMC API to a device as exported to CSV by WMC.
+func deviceFromAPI(d client.EndDevice) Device {
+	dev := Device{
+		DevEui:            d.DevEui,
+		ClusterID:         strconv.Itoa(d.ClusterID),
+		Name:              d.Name,
+		ClassType:         d.ClassType,
+		RfRegion:          d.RfRegion,
+		Country:           d.Country,
+		MacVersion:        d.MacVersion,
+		RegParamsRevision: d.RegParamsRevision,
+		Profile:           d.Profile,
+		AdrEnabled:        formatBool(d.AdrEnabled),
+		Activation:        d.Activation,
+		AppEui:            d.AppEui,
+		AppKey:            d.AppKey,
+		FCntDown:          formatInt(d.FCntDown, 10),
+		FCntUp:            formatInt(d.FCntUp, 10),
+		FNwkSIntKey:       d.FNwkSIntKey,
+		SNwkSIntKey:       d.SNwkSIntKey,
+		Rx1Delay:          formatInt(d.Rx1Delay, 10),
+		Rx1DrOffset:       formatInt(d.Rx1DrOffset, 10),
+		Rx2Dr:             formatInt(d.Rx2Dr, 16),
+		Rx2Freq:           formatFloat(d.Rx2Freq),
+		RxWindows:         d.RxWindows,
+		CfList:            d.CfList,
+		PingSlotDr:        formatInt(d.PingSlotDr, 10),
+		PingSlotFreq:      formatFloat(d.PingSlotFreq),
+		Latitude:          formatFloat(d.Latitude),
+		Longitude:         formatFloat(d.Longitude),
+		Altitude:          formatFloat(d.Altitude),
+		Status:            d.Status,
+		DevAddr:           d.DevAddr,
+		NwkSKey:           d.NwkSKey,
+		AppSKey:           d.AppSKey,
+	}
+	if d.DwellTime != nil {
+		dev.DwellTime = formatBool(*d.DwellTime)
+	}
+	// LoRaWAN 1.1 devices have a network session encryption key instead of a network session key.
+	if dev.NwkSKey == "" {
+		dev.NwkSKey = d.NwkSEncKey
+	}
+	if len(dev.DevAddr)%2 != 0 {
+		dev.DevAddr = "0" + dev.DevAddr
+	}
+	return dev
+}
@@ -0,0 +1,250 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client provides a client for the REST API of Wanesy Management Center (WMC).
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/httpclient"
+	"go.thethings.network/lorawan-stack-migrate/pkg/log"
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+)
+
+// basePath is the path of the WMC application API.
+const basePath = "/gms/application/"
+
+// pageSize is the number of items that are requested per page.
+const pageSize = 100
+
+// Config is the WMC client configuration.
+type Config struct {
+	URL        string
+	Username   string
+	Password   string
+	CACertPath string
+
+	HTTP httpclient.Config
+}
+
+// Client is a WMC client.
+type Client struct {
+	*Config
+	*http.Client
+	ctx context.Context
+
+	baseURL *url.URL
+	token   string
+}
+
+var (
+	errInvalidURL           = errors.DefineInvalidArgument("invalid_url", "invalid URL `{url}`")
+	errResourceNotFound     = errors.DefineNotFound("resource_not_found", "resource `{resource}` not found")
+	errUnauthenticated      = errors.DefineUnauthenticated("unauthenticated", "invalid WMC username or password")
+	errServer               = errors.Define("server", "server error with code `{code}`")
+	errUnexpectedStatusCode = errors.Define("unexpected_status_code", "unexpected status code `{code}`")
+)
+
+// NewClient creates a new WMC client.
+func (cfg *Config) NewClient(ctx context.Context) (*Client, error) {
+	baseURL, err := url.Parse(strings.TrimSuffix(cfg.URL, "/") + basePath)
+	if err != nil || baseURL.Host == "" {
+		return nil, errInvalidURL.WithAttributes("url", cfg.URL)
+	}
+	httpTransport := &http.Transport{}
+	if cfg.CACertPath != "" {
+		pemBytes, err := os.ReadFile(cfg.CACertPath)
+		if err != nil {
+			return nil, err
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		rootCAs.AppendCertsFromPEM(pemBytes)
+		httpTransport.TLSClientConfig = &tls.Config{
+			RootCAs: rootCAs,
+		}
+	}
+	return &Client{
+		Config:  cfg,
+		Client:  cfg.HTTP.New(httpTransport, log.FromContext(ctx)),
+		ctx:     ctx,
+		baseURL: baseURL,
+	}, nil
+}
+
+// login retrieves a token with the username and password.
+func (c *Client) login() error {
+	body, err := json.Marshal(struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}{
+		Login:    c.Username,
+		Password: c.Password,
+	})
+	if err != nil {
+		return err
+	}
+	res, err := c.send(http.MethodPost, "login", nil, body)
+	if err != nil {
+		if errors.IsUnauthenticated(err) {
+			return errUnauthenticated.New()
+		}
+		return err
+	}
+	var token struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(res, &token); err != nil {
+		return err
+	}
+	c.token = token.Token
+	return nil
+}
+
+// send executes an HTTP request.
+func (c *Client) send(method, resource string, params url.Values, body []byte) ([]byte, error) {
+	u := c.baseURL.JoinPath(resource)
+	u.RawQuery = params.Encode()
+
+	logger := log.FromContext(c.ctx).With("url", httpclient.RedactURL(u))
+	logger.Debug("Request resource")
+	req, err := http.NewRequestWithContext(c.ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := c.Client.Do(req)
+	if err != nil {
+		return nil, httpclient.RedactError(err)
+	}
+	defer res.Body.Close()
+	body, err = io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode < 300:
+		return body, nil
+	case res.StatusCode == http.StatusUnauthorized:
+		return nil, errUnauthenticated.New()
+	case res.StatusCode == http.StatusNotFound:
+		return nil, errResourceNotFound.WithAttributes("resource", resource)
+	case res.StatusCode >= 500:
+		return nil, errServer.WithAttributes("code", res.StatusCode)
+	default:
+		return nil, errUnexpectedStatusCode.WithAttributes("code", res.StatusCode)
+	}
+}
+
+// do executes an authenticated HTTP request. The client logs in again if the token is expired.
+func (c *Client) do(method, resource string, params url.Values, body []byte) ([]byte, error) {
+	if c.token == "" {
+		if err := c.login(); err != nil {
+			return nil, err
+		}
+	}
+	res, err := c.send(method, resource, params, body)
+	if errors.IsUnauthenticated(err) {
+		if err := c.login(); err != nil {
+			return nil, err
+		}
+		return c.send(method, resource, params, body)
+	}
+	return res, err
+}
+
+// list gets all pages of a paginated resource, and calls f with the items of each page.
+func (c *Client) list(resource string, f func(json.RawMessage) (int, error)) error {
+	for n, read := 1, 0; ; n++ {
+		params := url.Values{
+			"page":     []string{strconv.Itoa(n)},
+			"pageSize": []string{strconv.Itoa(pageSize)},
+		}
+		body, err := c.do(http.MethodGet, resource, params, nil)
+		if err != nil {
+			return err
+		}
+		var p page
+		if err := json.Unmarshal(body, &p); err != nil {
+			return err
+		}
+		count, err := f(p.List)
+		if err != nil {
+			return err
+		}
+		read += count
+		if count == 0 || read >= p.TotalCount {
+			return nil
+		}
+	}
+}
+
+// GetClusters gets all clusters that the user has access to.
+func (c *Client) GetClusters() ([]Cluster, error) {
+	var clusters []Cluster
+	err := c.list("clusters", func(list json.RawMessage) (int, error) {
+		var page []Cluster
+		if err := json.Unmarshal(list, &page); err != nil {
+			return 0, err
+		}
+		clusters = append(clusters, page...)
+		return len(page), nil
+	})
+	return clusters, err
+}
+
+// GetClusterDevices gets all devices of a cluster.
+func (c *Client) GetClusterDevices(clusterID int) ([]EndDevice, error) {
+	var devs []EndDevice
+	err := c.list(fmt.Sprintf("clusters/%d/endDevices", clusterID), func(list json.RawMessage) (int, error) {
+		var page []EndDevice
+		if err := json.Unmarshal(list, &page); err != nil {
+			return 0, err
+		}
+		devs = append(devs, page...)
+		return len(page), nil
+	})
+	return devs, err
+}
+
+// GetDevice gets a device by the DevEUI.
+func (c *Client) GetDevice(devEUI string) (*EndDevice, error) {
+	body, err := c.do(http.MethodGet, "endDevices/"+devEUI, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var dev EndDevice
+	if err := json.Unmarshal(body, &dev); err != nil {
+		return nil, err
+	}
+	return &dev, nil
+}
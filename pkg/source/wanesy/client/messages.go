@@ -0,0 +1,69 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import "encoding/json"
+
+// Cluster is a WMC cluster. Clusters group the devices of a customer.
+type Cluster struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	CustomerID  int    `json:"customerId,omitempty"`
+}
+
+// EndDevice is a WMC end device. Keys are only returned if the user is allowed to read them.
+type EndDevice struct {
+	DevEui            string   `json:"devEui"`
+	ClusterID         int      `json:"clusterId"`
+	Name              string   `json:"name,omitempty"`
+	ClassType         string   `json:"classType,omitempty"`
+	RfRegion          string   `json:"rfRegion,omitempty"`
+	Country           string   `json:"country,omitempty"`
+	MacVersion        string   `json:"macVersion,omitempty"`
+	RegParamsRevision string   `json:"regParamsRevision,omitempty"`
+	Profile           string   `json:"profile,omitempty"`
+	AdrEnabled        bool     `json:"adrEnabled,omitempty"`
+	Activation        string   `json:"activation,omitempty"`
+	AppEui            string   `json:"appEui,omitempty"`
+	AppKey            string   `json:"appKey,omitempty"`
+	DevAddr           string   `json:"devAddr,omitempty"`
+	NwkSKey           string   `json:"nwkSKey,omitempty"`
+	AppSKey           string   `json:"appSKey,omitempty"`
+	FNwkSIntKey       string   `json:"fNwkSIntKey,omitempty"`
+	SNwkSIntKey       string   `json:"sNwkSIntKey,omitempty"`
+	NwkSEncKey        string   `json:"nwkSEncKey,omitempty"`
+	FCntDown          *int     `json:"fcntDown,omitempty"`
+	FCntUp            *int     `json:"fcntUp,omitempty"`
+	Rx1Delay          *int     `json:"rx1Delay,omitempty"`
+	Rx1DrOffset       *int     `json:"rx1DrOffset,omitempty"`
+	Rx2Dr             *int     `json:"rx2Dr,omitempty"`
+	Rx2Freq           *float64 `json:"rx2Freq,omitempty"`
+	RxWindows         string   `json:"rxWindows,omitempty"`
+	CfList            string   `json:"cfList,omitempty"`
+	DwellTime         *bool    `json:"dwellTime,omitempty"`
+	PingSlotDr        *int     `json:"pingSlotDr,omitempty"`
+	PingSlotFreq      *float64 `json:"pingSlotFreq,omitempty"`
+	Latitude          *float64 `json:"latitude,omitempty"`
+	Longitude         *float64 `json:"longitude,omitempty"`
+	Altitude          *float64 `json:"altitude,omitempty"`
+	Status            string   `json:"status,omitempty"`
+}
+
+// page is a page of a paginated WMC resource.
+type page struct {
+	List       json.RawMessage `json:"list"`
+	TotalCount int             `json:"totalCount"`
+}
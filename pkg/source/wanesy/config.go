@@ -15,21 +15,23 @@
 package wanesy
 
 import (
-	"encoding/csv"
-	"encoding/json"
+	"io"
 	"net/http"
 	"os"
-	"strings"
 
 	"github.com/spf13/pflag"
 
+	"go.thethings.network/lorawan-stack-migrate/pkg/csv"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/wanesy/client"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
 	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
 	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
 	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
 )
 
 type Config struct {
+	client.Config
 	src source.Config
 
 	appID           string
@@ -37,6 +39,12 @@ type Config struct {
 	csvPath         string
 	all             bool
 
+	CSV csv.Config
+
+	appIDMappingPath string
+	// appIDs maps cluster IDs and names to application IDs.
+	appIDs map[string]string
+
 	derivedMacVersion ttnpb.MACVersion
 	derivedPhyVersion ttnpb.PHYVersion
 
@@ -56,7 +64,8 @@ func NewConfig() *Config {
 	config.flags.StringVar(&config.appID,
 		"app-id",
 		os.Getenv("APP_ID"),
-		"Application ID for the exported devices")
+		`Application ID for the exported devices.
+If not set, the application ID is derived from the cluster name of the device`)
 	config.flags.StringVar(&config.csvPath,
 		"csv-path",
 		os.Getenv("CSV_PATH"),
@@ -64,7 +73,31 @@ func NewConfig() *Config {
 	config.flags.BoolVar(&config.all,
 		"all",
 		false,
-		"Export all devices in the CSV. This is only used by the application command")
+		`Export all devices in the CSV or of all clusters. This is only used by the application command.
+Without this flag, the application command exports the devices of the given cluster IDs or names`)
+	config.flags.StringVar(&config.appIDMappingPath,
+		"app-id-mapping-file",
+		os.Getenv("WANESY_APP_ID_MAPPING_FILE"),
+		`(optional) Path to a file that maps cluster IDs or names to application IDs, one mapping per line.
+Devices of clusters that are not mapped use the application ID set with --app-id`)
+	config.flags.StringVar(&config.URL,
+		"url",
+		os.Getenv("WANESY_URL"),
+		"URL of Wanesy Management Center, to export devices with the WMC API instead of a CSV file")
+	config.flags.StringVar(&config.Username,
+		"username",
+		os.Getenv("WANESY_USERNAME"),
+		"Username of the WMC API")
+	config.flags.StringVar(&config.Password,
+		"password",
+		"",
+		"Password of the WMC API")
+	config.flags.StringVar(&config.CACertPath,
+		"ca-cert-path",
+		os.Getenv("WANESY_CA_CERT_PATH"),
+		"(optional) Path to the CA certificate for the WMC API")
+	config.flags.AddFlagSet(config.CSV.Flags("WANESY_"))
+	config.flags.AddFlagSet(config.HTTP.Flags("WANESY_"))
 	return config
 }
 
@@ -72,14 +105,32 @@ func NewConfig() *Config {
 func (c *Config) Initialize(src source.Config) error {
 	c.src = src
 
-	if c.appID == "" {
-		return errNoAppID.New()
+	if c.appIDMappingPath != "" {
+		mapping, err := util.ReadMappingFile(c.appIDMappingPath)
+		if err != nil {
+			return err
+		}
+		// Cluster IDs are mapped without leading zeros, so that they match in the CSV file and the WMC API.
+		c.appIDs = make(map[string]string, len(mapping))
+		for key, appID := range mapping {
+			c.appIDs[clusterID(key)] = appID
+		}
 	}
 	if c.frequencyPlanID == "" {
 		return errNoFrequencyPlanID.New()
 	}
-	if c.csvPath == "" {
+	if password := os.Getenv("WANESY_PASSWORD"); password != "" && c.Password == "" {
+		c.Password = password
+	}
+	switch {
+	case c.csvPath != "":
+		if err := c.CSV.Initialize(); err != nil {
+			return err
+		}
+	case c.URL == "":
 		return errNoCSVFileProvided.New()
+	case c.Username == "" || c.Password == "":
+		return errNoCredentials.New()
 	}
 
 	fpFetcher, err := fetch.FromHTTP(http.DefaultClient, src.FrequencyPlansURL)
@@ -96,42 +147,39 @@ func (c *Config) Flags() *pflag.FlagSet {
 	return c.flags
 }
 
-// ImportDevices imports the devices from the provided CSV file.
-func ImportDevices(csvPath string) (Devices, error) {
-	raw, err := os.ReadFile(csvPath)
+// ImportDevices imports the devices from the provided CSV file. The file is read one record at a time,
+// and errors of invalid records contain the line number.
+func ImportDevices(csvPath string, config csv.Config) (Devices, error) {
+	f, err := os.Open(csvPath)
 	if err != nil {
 		return nil, err
 	}
-	reader := csv.NewReader(strings.NewReader(string(raw)))
-	readValues, err := reader.ReadAll()
+	defer f.Close()
+
+	reader, err := config.NewReader(f, "devEui")
 	if err != nil {
 		return nil, err
 	}
-	if len(readValues) < 2 {
-		return nil, errNoValuesInCSV.New()
-	}
-	values := make([]map[string]string, 0)
-	for i := 1; i < len(readValues); i++ {
-		keys := readValues[0]
-		value := make(map[string]string)
-		for j := 0; j < len(keys); j++ {
-			noOfcolumns := len(readValues[i])
-			if j >= noOfcolumns {
-				value[keys[j]] = "" // Fill empty columns.
-				continue
-			}
-			value[keys[j]] = readValues[i][j]
+	devices := make(Devices)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var dev Device
+		if err := record.Decode(&dev); err != nil {
+			return nil, record.Error(err)
+		}
+		dev.ClusterID = clusterID(dev.ClusterID)
+		if err := devices.add(dev); err != nil {
+			return nil, record.Error(err)
 		}
-		values = append(values, value)
-	}
-	j, err := json.Marshal(values)
-	if err != nil {
-		return nil, err
 	}
-	devices := make(Devices)
-	err = devices.UnmarshalJSON(j)
-	if err != nil {
-		return nil, err
+	if len(devices) == 0 {
+		return nil, errNoValuesInCSV.New()
 	}
 	return devices, nil
 }
@@ -0,0 +1,97 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wanesy
+
+import (
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+// allClusters is the cluster that is used to export the devices of all clusters.
+const allClusters = "all"
+
+// clusterID returns the cluster ID without leading zeros. The CSV export of WMC contains cluster IDs with
+// leading zeros, for example `0001`, while the WMC API uses numbers. IDs that are not numbers are returned as is.
+func clusterID(id string) string {
+	if n, err := strconv.ParseUint(id, 10, 64); err == nil {
+		return strconv.FormatUint(n, 10)
+	}
+	return id
+}
+
+// matchesCluster returns true if the device is in the cluster. Clusters are matched by ID, name or
+// application ID that is derived from the name.
+func (d Device) matchesCluster(cluster string) bool {
+	if cluster == allClusters {
+		return true
+	}
+	return d.ClusterID == clusterID(cluster) ||
+		strings.EqualFold(d.ClusterName, cluster) ||
+		(d.ClusterName != "" && util.Slug(d.ClusterName) == cluster)
+}
+
+// clusterName returns the name of the cluster. The clusters of the WMC API are retrieved once.
+func (s Source) clusterName(dev Device) (string, error) {
+	if dev.ClusterName != "" || s.Client == nil {
+		return dev.ClusterName, nil
+	}
+	if len(s.clusterNames) == 0 {
+		clusters, err := s.GetClusters()
+		if err != nil {
+			return "", err
+		}
+		for _, cluster := range clusters {
+			s.clusterNames[strconv.Itoa(cluster.ID)] = cluster.Name
+		}
+	}
+	return s.clusterNames[dev.ClusterID], nil
+}
+
+// applicationID returns the application ID of the device. Clusters in the mapping file are mapped by
+// ID or name. Devices of clusters that are not mapped use the configured application ID, or an
+// application ID that is derived from the cluster name. Cluster names that can not be converted to a
+// valid application ID are replaced by the cluster ID, for example `cluster-1`.
+func (s Source) applicationID(dev Device) (string, error) {
+	for _, key := range []string{dev.ClusterID, dev.ClusterName} {
+		if appID, ok := s.appIDs[key]; ok && key != "" {
+			return appID, nil
+		}
+	}
+	if s.appID != "" {
+		return s.appID, nil
+	}
+	appID := util.Slug(dev.ClusterName)
+	if util.ValidateID(appID) != nil && dev.ClusterID != "" {
+		appID = util.Slug("cluster-" + dev.ClusterID)
+	}
+	if appID == "" {
+		return "", errNoAppID.New()
+	}
+	if err := util.ValidateID(appID); err != nil {
+		return "", err
+	}
+	// Clusters with similar names may be converted to the same application ID.
+	if other, ok := s.clusterAppIDs[appID]; ok && other != dev.ClusterID {
+		return "", errApplicationIDConflict.WithAttributes(
+			"application_id", appID,
+			"cluster_id", dev.ClusterID,
+			"other_cluster_id", other,
+		)
+	}
+	s.clusterAppIDs[appID] = dev.ClusterID
+	return appID, nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wanesy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+
+	"go.thethings.network/lorawan-stack-migrate/pkg/source"
+	_ "go.thethings.network/lorawan-stack-migrate/pkg/source/wanesy"
+)
+
+func TestApplicationIDMapping(t *testing.T) {
+	a := assertions.New(t)
+	flags, err := source.FlagSet("wanesy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{
+		"csv-path":            "./testdata/clusters.csv",
+		"frequency-plan-id":   "EU_863_870",
+		"app-id-mapping-file": "./testdata/mapping.txt",
+	} {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	source.RootConfig.SetSource("wanesy")
+	source.RootConfig.FrequencyPlansURL = "https://raw.githubusercontent.com/TheThingsNetwork/lorawan-frequency-plans/master"
+	src, err := source.NewSource(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to create source: %v", err)
+	}
+
+	for _, tc := range []struct {
+		cluster string
+		devEUI  string
+		appID   string
+	}{
+		// Mapped by the cluster name.
+		{cluster: "Smart Building", devEUI: "2222222222222221", appID: "building-b"},
+		// Mapped by the cluster ID, with or without leading zeros.
+		{cluster: "0003", devEUI: "2222222222222222", appID: "warehouse"},
+		{cluster: "3", devEUI: "2222222222222222", appID: "warehouse"},
+		// Not mapped, so the application ID is derived from the cluster name.
+		{cluster: "garage", devEUI: "2222222222222223", appID: "garage"},
+		// The cluster name is too short for an application ID, so the cluster ID is used.
+		{cluster: "A", devEUI: "2222222222222224", appID: "cluster-6"},
+	} {
+		t.Run(tc.cluster, func(t *testing.T) {
+			a := assertions.New(t)
+			var devEUIs []string
+			err := src.RangeDevices(tc.cluster, func(_ source.Source, devEUI string) error {
+				devEUIs = append(devEUIs, devEUI)
+				return nil
+			})
+			a.So(err, should.BeNil)
+			a.So(devEUIs, should.Resemble, []string{tc.devEUI})
+
+			dev, err := src.ExportDevice(tc.devEUI)
+			if !a.So(err, should.BeNil) {
+				t.FailNow()
+			}
+			a.So(dev.Ids.ApplicationIds.ApplicationId, should.Equal, tc.appID)
+		})
+	}
+
+	a.So(src.RangeDevices("0005", func(source.Source, string) error {
+		t.Error("Unexpected device")
+		return nil
+	}), should.BeNil)
+}
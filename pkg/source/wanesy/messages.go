@@ -15,8 +15,8 @@
 package wanesy
 
 import (
-	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/TheThingsNetwork/go-utils/random"
@@ -31,23 +31,20 @@ import (
 // Devices is a list of devices.
 type Devices map[types.EUI64]Device
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (d Devices) UnmarshalJSON(b []byte) error {
-	var devs []Device
-	if err := json.Unmarshal(b, &devs); err != nil {
+// add adds the device. DevAddrs with an odd length are padded with a leading zero.
+func (d Devices) add(dev Device) error {
+	var devEUI types.EUI64
+	if err := devEUI.UnmarshalText([]byte(dev.DevEui)); err != nil {
 		return err
 	}
-	for _, dev := range devs {
-		var devEUI types.EUI64
-		if err := devEUI.UnmarshalText([]byte(dev.DevEui)); err != nil {
-			return err
-		}
-		if len(dev.DevAddr)%2 != 0 {
-			// Prepend 0 to make the length 8.
-			dev.DevAddr = fmt.Sprintf("0%s", dev.DevAddr)
-		}
-		d[devEUI] = dev
+	if _, ok := d[devEUI]; ok {
+		return errDuplicateDevice.WithAttributes("eui", dev.DevEui)
+	}
+	if len(dev.DevAddr)%2 != 0 {
+		// Prepend 0 to make the length 8.
+		dev.DevAddr = fmt.Sprintf("0%s", dev.DevAddr)
 	}
+	d[devEUI] = dev
 	return nil
 }
 
@@ -128,15 +125,6 @@ func (d Device) EndDevice(fpStore *frequencyplans.Store, applicationID, frequenc
 		}
 		ret.Ids.JoinEui = joinEUI.Bytes()
 	}
-	if d.Rx2Dr != "" {
-		s, err := strconv.ParseUint(d.Rx2Dr, 16, 32)
-		if err != nil {
-			return nil, err
-		}
-		ret.MacSettings.DesiredRx2DataRateIndex = &ttnpb.DataRateIndexValue{
-			Value: ttnpb.DataRateIndex(int32(s)),
-		}
-	}
 	switch d.MacVersion {
 	case "1.0.0":
 		ret.LorawanVersion = ttnpb.MACVersion_MAC_V1_0
@@ -184,20 +172,47 @@ func (d Device) EndDevice(fpStore *frequencyplans.Store, applicationID, frequenc
 		return nil, errInvalidMACVersion.WithAttributes("mac_version", d.MacVersion)
 	}
 
-	if d.Longitude != "" && d.Latitude != "" && d.Altitude != "" {
-		latitude, _ := strconv.ParseFloat(d.Latitude, 64)
-		longitude, _ := strconv.ParseFloat(d.Longitude, 64)
-		altitude, err := strconv.ParseUint(d.Rx2Dr, 16, 32)
+	if err := d.setMACSettings(ret.MacSettings); err != nil {
+		return nil, err
+	}
+	if d.CfList != "" {
+		cfList, err := parseCFList(d.CfList)
 		if err != nil {
 			return nil, err
 		}
+		if len(cfList) > 0 {
+			ret.MacSettings.FactoryPresetFrequencies, err = factoryPresetFrequencies(
+				fpStore, frequencyPlanID, ret.LorawanPhyVersion, cfList,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if d.Longitude != "" && d.Latitude != "" {
+		latitude, err := strconv.ParseFloat(d.Latitude, 64)
+		if err != nil {
+			return nil, err
+		}
+		longitude, err := strconv.ParseFloat(d.Longitude, 64)
+		if err != nil {
+			return nil, err
+		}
+		location := &ttnpb.Location{
+			Latitude:  latitude,
+			Longitude: longitude,
+			Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
+		}
+		if d.Altitude != "" {
+			altitude, err := strconv.ParseFloat(d.Altitude, 64)
+			if err != nil {
+				return nil, err
+			}
+			location.Altitude = int32(math.Round(altitude))
+		}
 		ret.Locations = map[string]*ttnpb.Location{
-			"user": {
-				Latitude:  latitude,
-				Longitude: longitude,
-				Altitude:  int32(altitude),
-				Source:    ttnpb.LocationSource_SOURCE_REGISTRY,
-			},
+			"user": location,
 		}
 	}
 
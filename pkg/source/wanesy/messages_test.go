@@ -20,6 +20,7 @@ import (
 
 	"github.com/smarty/assertions"
 	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack-migrate/pkg/csv"
 	. "go.thethings.network/lorawan-stack-migrate/pkg/source/wanesy"
 	"go.thethings.network/lorawan-stack/v3/pkg/fetch"
 	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
@@ -29,7 +30,7 @@ import (
 
 func TestImportAndExport(t *testing.T) {
 	a := assertions.New(t)
-	devices, err := ImportDevices("./testdata/test.csv")
+	devices, err := ImportDevices("./testdata/test.csv", csv.Config{})
 	a.So(err, should.BeNil)
 	a.So(len(devices), should.Equal, 1)
 	var devEUI types.EUI64
@@ -69,3 +70,63 @@ func TestImportAndExport(t *testing.T) {
 	a.So(v3Device.LorawanVersion, should.Equal, ttnpb.MACVersion_MAC_V1_0_2)
 	a.So(v3Device.Session.Keys, should.NotBeNil)
 }
+
+func TestRadioParameters(t *testing.T) {
+	a := assertions.New(t)
+	fpFetcher, err := fetch.FromHTTP(
+		http.DefaultClient,
+		"https://raw.githubusercontent.com/TheThingsNetwork/lorawan-frequency-plans/master",
+	)
+	if err != nil {
+		t.Fatalf("Failed to create fetcher: %v", err)
+	}
+	dev := Device{
+		DevEui:            "1111111111111111",
+		ClassType:         "B",
+		MacVersion:        "1.0.3",
+		RegParamsRevision: "A",
+		AdrEnabled:        "False",
+		Activation:        "ABP",
+		FCntDown:          "10",
+		FCntUp:            "20",
+		Rx1DrOffset:       "1",
+		Rx2Dr:             "3",
+		Rx2Freq:           "868.525",
+		CfList:            "184F84E85684B85E84886684586E8400",
+		DwellTime:         "UPLINK",
+		PingSlotDr:        "2",
+		PingSlotFreq:      "869462500",
+		Latitude:          "52.37",
+		Longitude:         "4.89",
+		Altitude:          "12.6",
+		DevAddr:           "01234567",
+		NwkSKey:           "33333333333333333333333333333333",
+		AppSKey:           "44444444444444444444444444444444",
+	}
+	v3Device, err := dev.EndDevice(frequencyplans.NewStore(fpFetcher), "test-app", "EU_863_870")
+	if err != nil {
+		t.Fatalf("Failed to convert device: %v", err)
+	}
+
+	settings := v3Device.MacSettings
+	a.So(settings.Adr.GetDisabled(), should.NotBeNil)
+	a.So(settings.DesiredRx1DataRateOffset.GetValue(), should.Equal, ttnpb.DataRateOffset_DATA_RATE_OFFSET_1)
+	a.So(settings.DesiredRx2DataRateIndex.GetValue(), should.Equal, ttnpb.DataRateIndex_DATA_RATE_3)
+	a.So(settings.DesiredRx2Frequency.GetValue(), should.Equal, 868525000)
+	a.So(settings.DesiredPingSlotDataRateIndex.GetValue(), should.Equal, ttnpb.DataRateIndex_DATA_RATE_2)
+	a.So(settings.DesiredPingSlotFrequency.GetValue(), should.Equal, 869462500)
+	a.So(settings.UplinkDwellTime.GetValue(), should.BeTrue)
+	a.So(settings.DownlinkDwellTime.GetValue(), should.BeFalse)
+	a.So(settings.FactoryPresetFrequencies, should.Resemble, []uint64{
+		868100000, 868300000, 868500000, 867100000, 867300000, 867500000, 867700000, 867900000,
+	})
+
+	a.So(v3Device.MacState, should.NotBeNil)
+	current := v3Device.MacState.CurrentParameters
+	a.So(current.Rx2Frequency, should.Equal, 868525000)
+	a.So(current.PingSlotFrequency, should.Equal, 869462500)
+	a.So(current.UplinkDwellTime.GetValue(), should.BeTrue)
+
+	a.So(v3Device.Locations["user"], should.NotBeNil)
+	a.So(v3Device.Locations["user"].Altitude, should.Equal, 13)
+}
@@ -0,0 +1,174 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wanesy
+
+import (
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/band"
+	"go.thethings.network/lorawan-stack/v3/pkg/frequencyplans"
+	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
+)
+
+// cfListTypeFrequencies is the CFList type with a list of frequencies.
+const cfListTypeFrequencies = 0
+
+// parseBool parses a boolean as exported by WMC.
+func parseBool(s string) (bool, error) {
+	return strconv.ParseBool(strings.ToLower(s))
+}
+
+// parseFrequency parses a frequency in MHz or Hz.
+func parseFrequency(s string) (uint64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errInvalidFrequency.WithAttributes("frequency", s).WithCause(err)
+	}
+	if f < 0 {
+		return 0, errInvalidFrequency.WithAttributes("frequency", s)
+	}
+	if f < 1e4 {
+		f *= 1e6
+	}
+	return uint64(math.Round(f)), nil
+}
+
+// parseCFList parses the frequencies of a CFList. The CFList is either the hex encoded CFList of the
+// join-accept message, or a list of frequencies in MHz or Hz. CFLists with a channel mask do not contain
+// frequencies, and nil is returned.
+func parseCFList(s string) ([]uint64, error) {
+	if b, err := hex.DecodeString(s); err == nil && len(b) == 16 {
+		if b[15] != cfListTypeFrequencies {
+			return nil, nil
+		}
+		var freqs []uint64
+		for i := 0; i < 15; i += 3 {
+			// Frequencies are 24-bit little endian in units of 100 Hz.
+			if freq := uint64(b[i]) | uint64(b[i+1])<<8 | uint64(b[i+2])<<16; freq > 0 {
+				freqs = append(freqs, freq*100)
+			}
+		}
+		return freqs, nil
+	}
+	var freqs []uint64
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	}) {
+		freq, err := parseFrequency(field)
+		if err != nil {
+			return nil, errInvalidCFList.WithAttributes("cf_list", s).WithCause(err)
+		}
+		freqs = append(freqs, freq)
+	}
+	return freqs, nil
+}
+
+// setMACSettings sets the ADR, Rx window, class B ping slot and dwell time settings of the device.
+func (d Device) setMACSettings(m *ttnpb.MACSettings) error {
+	if d.AdrEnabled != "" {
+		enabled, err := parseBool(d.AdrEnabled)
+		if err != nil {
+			return err
+		}
+		if enabled {
+			m.Adr = &ttnpb.ADRSettings{Mode: &ttnpb.ADRSettings_Dynamic{Dynamic: &ttnpb.ADRSettings_DynamicMode{}}}
+		} else {
+			m.Adr = &ttnpb.ADRSettings{Mode: &ttnpb.ADRSettings_Disabled{Disabled: &ttnpb.ADRSettings_DisabledMode{}}}
+		}
+	}
+	if d.Rx1DrOffset != "" {
+		offset, err := strconv.ParseUint(d.Rx1DrOffset, 10, 32)
+		if err != nil {
+			return err
+		}
+		m.DesiredRx1DataRateOffset = &ttnpb.DataRateOffsetValue{Value: ttnpb.DataRateOffset(offset)}
+	}
+	if d.Rx2Dr != "" {
+		s, err := strconv.ParseUint(d.Rx2Dr, 16, 32)
+		if err != nil {
+			return err
+		}
+		m.DesiredRx2DataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(int32(s))}
+	}
+	if d.Rx2Freq != "" {
+		freq, err := parseFrequency(d.Rx2Freq)
+		if err != nil {
+			return err
+		}
+		m.DesiredRx2Frequency = &ttnpb.FrequencyValue{Value: freq}
+	}
+
+	// Class B
+	if d.PingSlotDr != "" {
+		dr, err := strconv.ParseUint(d.PingSlotDr, 10, 32)
+		if err != nil {
+			return err
+		}
+		m.DesiredPingSlotDataRateIndex = &ttnpb.DataRateIndexValue{Value: ttnpb.DataRateIndex(dr)}
+	}
+	if d.PingSlotFreq != "" {
+		freq, err := parseFrequency(d.PingSlotFreq)
+		if err != nil {
+			return err
+		}
+		m.DesiredPingSlotFrequency = &ttnpb.ZeroableFrequencyValue{Value: freq}
+	}
+
+	// Dwell time
+	switch strings.ToUpper(d.DwellTime) {
+	case "":
+	case "UPLINK", "UP":
+		m.UplinkDwellTime = &ttnpb.BoolValue{Value: true}
+		m.DownlinkDwellTime = &ttnpb.BoolValue{Value: false}
+	case "DOWNLINK", "DOWN":
+		m.UplinkDwellTime = &ttnpb.BoolValue{Value: false}
+		m.DownlinkDwellTime = &ttnpb.BoolValue{Value: true}
+	case "BOTH":
+		m.UplinkDwellTime = &ttnpb.BoolValue{Value: true}
+		m.DownlinkDwellTime = &ttnpb.BoolValue{Value: true}
+	case "NONE":
+		m.UplinkDwellTime = &ttnpb.BoolValue{Value: false}
+		m.DownlinkDwellTime = &ttnpb.BoolValue{Value: false}
+	default:
+		enabled, err := parseBool(d.DwellTime)
+		if err != nil {
+			return errInvalidDwellTime.WithAttributes("dwell_time", d.DwellTime)
+		}
+		m.UplinkDwellTime = &ttnpb.BoolValue{Value: enabled}
+		m.DownlinkDwellTime = &ttnpb.BoolValue{Value: enabled}
+	}
+	return nil
+}
+
+// factoryPresetFrequencies returns the default uplink channel frequencies of the band, followed by
+// the frequencies of the CFList.
+func factoryPresetFrequencies(fpStore *frequencyplans.Store, frequencyPlanID string, phyVersion ttnpb.PHYVersion, cfList []uint64) ([]uint64, error) {
+	fp, err := fpStore.GetByID(frequencyPlanID)
+	if err != nil {
+		return nil, err
+	}
+	phy, err := band.Get(fp.BandID, phyVersion)
+	if err != nil {
+		return nil, err
+	}
+	freqs := make([]uint64, 0, len(phy.UplinkChannels)+len(cfList))
+	for _, ch := range phy.UplinkChannels {
+		freqs = append(freqs, ch.Frequency)
+	}
+	return append(freqs, cfList...), nil
+}
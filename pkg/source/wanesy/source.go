@@ -17,13 +17,15 @@ package wanesy
 import (
 	"context"
 	"os"
+	"strconv"
 
 	"go.thethings.network/lorawan-stack/v3/pkg/ttnpb"
 	"go.thethings.network/lorawan-stack/v3/pkg/types"
 
 	"go.thethings.network/lorawan-stack-migrate/pkg/iterator"
+	"go.thethings.network/lorawan-stack-migrate/pkg/log"
 	"go.thethings.network/lorawan-stack-migrate/pkg/source"
-	"go.thethings.network/lorawan-stack-migrate/pkg/source/firefly/client"
+	"go.thethings.network/lorawan-stack-migrate/pkg/source/wanesy/client"
 )
 
 type Source struct {
@@ -31,6 +33,10 @@ type Source struct {
 	*client.Client
 
 	imported Devices
+	// clusterNames contains the names of WMC clusters by ID.
+	clusterNames map[string]string
+	// clusterAppIDs contains the cluster IDs by derived application ID.
+	clusterAppIDs map[string]string
 }
 
 func createNewSource(cfg *Config) source.CreateSource {
@@ -38,13 +44,26 @@ func createNewSource(cfg *Config) source.CreateSource {
 		if err := cfg.Initialize(src); err != nil {
 			return nil, err
 		}
-		devs, err := ImportDevices(cfg.csvPath)
+		if cfg.csvPath != "" {
+			devs, err := ImportDevices(cfg.csvPath, cfg.CSV)
+			if err != nil {
+				return nil, err
+			}
+			return Source{
+				Config:        cfg,
+				imported:      devs,
+				clusterAppIDs: make(map[string]string),
+			}, nil
+		}
+		client, err := cfg.NewClient(log.NewContext(ctx, src.Logger))
 		if err != nil {
 			return nil, err
 		}
 		return Source{
-			Config:   cfg,
-			imported: devs,
+			Config:        cfg,
+			Client:        client,
+			clusterNames:  make(map[string]string),
+			clusterAppIDs: make(map[string]string),
 		}, nil
 	}
 }
@@ -56,9 +75,9 @@ func (s Source) Iterator(isApplication bool) iterator.Iterator {
 	}
 	if s.all {
 		// WMC does not group devices by application.
-		// The `all` flag is used to export all the devices in the CSV.
+		// The `all` flag is used to export all the devices in the CSV, or of all clusters.
 		return iterator.NewListIterator(
-			[]string{"all"},
+			[]string{allClusters},
 		)
 	}
 	return iterator.NewNoopIterator()
@@ -71,10 +90,27 @@ func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
 		return nil, err
 	}
 	wmcdev, ok := s.imported[devEUI]
+	if s.Client != nil {
+		// Devices are retrieved separately, since the keys are not included when listing devices.
+		dev, err := s.GetDevice(devEUI.String())
+		if err != nil {
+			return nil, err
+		}
+		wmcdev, ok = deviceFromAPI(*dev), true
+	}
 	if !ok {
 		return nil, errNoDeviceFound.WithAttributes("eui", devEUIString)
 	}
-	v3dev, err := wmcdev.EndDevice(s.fpStore, s.appID, s.frequencyPlanID)
+	clusterName, err := s.clusterName(wmcdev)
+	if err != nil {
+		return nil, err
+	}
+	wmcdev.ClusterName = clusterName
+	appID, err := s.applicationID(wmcdev)
+	if err != nil {
+		return nil, err
+	}
+	v3dev, err := wmcdev.EndDevice(s.fpStore, appID, s.frequencyPlanID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +118,16 @@ func (s Source) ExportDevice(devEUIString string) (*ttnpb.EndDevice, error) {
 }
 
 // RangeDevices implements the source.Source interface.
-func (s Source) RangeDevices(_ string, f func(source.Source, string) error) error {
-	for eui := range s.imported {
+// Devices are read from the CSV file, or from the clusters of the WMC API. Only the devices of the
+// cluster with the given ID or name are exported, unless all devices are exported.
+func (s Source) RangeDevices(cluster string, f func(source.Source, string) error) error {
+	if s.Client != nil {
+		return s.rangeClusters(cluster, f)
+	}
+	for eui, dev := range s.imported {
+		if !dev.matchesCluster(cluster) {
+			continue
+		}
 		if err := f(s, eui.String()); err != nil {
 			return err
 		}
@@ -91,5 +135,31 @@ func (s Source) RangeDevices(_ string, f func(source.Source, string) error) erro
 	return nil
 }
 
+// rangeClusters calls f for the devices of the clusters of the WMC API.
+func (s Source) rangeClusters(cluster string, f func(source.Source, string) error) error {
+	clusters, err := s.GetClusters()
+	if err != nil {
+		return err
+	}
+	for _, c := range clusters {
+		clusterID := strconv.Itoa(c.ID)
+		s.clusterNames[clusterID] = c.Name
+		if !(Device{ClusterID: clusterID, ClusterName: c.Name}).matchesCluster(cluster) {
+			continue
+		}
+		s.src.Logger.Debugw("Export cluster", "cluster_id", c.ID, "cluster_name", c.Name)
+		devs, err := s.GetClusterDevices(c.ID)
+		if err != nil {
+			return err
+		}
+		for _, dev := range devs {
+			if err := f(s, dev.DevEui); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Close implements the Source interface.
 func (s Source) Close() error { return nil }
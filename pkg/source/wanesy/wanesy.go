@@ -12,7 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package wanesy provides functions to parse the WMC csv file and create a TTS device JSON.
+// Package wanesy provides functions to parse the WMC csv file, or retrieve devices from the WMC API,
+// and create a TTS device JSON.
 package wanesy
 
 import (
@@ -23,12 +24,21 @@ import (
 var (
 	errNoValuesInCSV           = errors.DefineInvalidArgument("no_values_in_csv", "no values in CSV file")
 	errNoAppID                 = errors.DefineInvalidArgument("no_app_id", "no app id")
-	errNoCSVFileProvided       = errors.DefineInvalidArgument("no_csv_file_provided", "no csv file provided")
+	errNoCSVFileProvided       = errors.DefineInvalidArgument("no_csv_file_provided", "no csv file or WMC URL provided")
+	errNoCredentials           = errors.DefineInvalidArgument("no_credentials", "no WMC username or password")
 	errNoJoinEUI               = errors.DefineInvalidArgument("no_join_eui", "no join eui")
+	errDuplicateDevice         = errors.DefineAlreadyExists("duplicate_device", "duplicate device with eui `{eui}`")
 	errNoDeviceFound           = errors.DefineInvalidArgument("no_device_found", "no device with eui `{eui}` found")
 	errNoFrequencyPlanID       = errors.DefineInvalidArgument("no_frequency_plan_id", "no frequency plan ID")
 	errInvalidMACVersion       = errors.DefineInvalidArgument("invalid_mac_version", "invalid MAC version `{mac_version}`")
 	errInvalidPHYForMACVersion = errors.DefineInvalidArgument("invalid_phy_for_mac_version", "invalid PHY version `{phy_version}` for MAC version `{mac_version}`")
+	errInvalidFrequency        = errors.DefineInvalidArgument("invalid_frequency", "invalid frequency `{frequency}`")
+	errInvalidCFList           = errors.DefineInvalidArgument("invalid_cf_list", "invalid CFList `{cf_list}`")
+	errInvalidDwellTime        = errors.DefineInvalidArgument("invalid_dwell_time", "invalid dwell time `{dwell_time}`")
+	errApplicationIDConflict   = errors.DefineAlreadyExists(
+		"application_id_conflict",
+		"application ID `{application_id}` of cluster `{cluster_id}` is already used by cluster `{other_cluster_id}`",
+	)
 )
 
 func init() {
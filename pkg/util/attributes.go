@@ -0,0 +1,72 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+)
+
+// maxAttributeValueLength is the maximum length of an attribute value on The Things Stack.
+const maxAttributeValueLength = 200
+
+var (
+	errInvalidAttributeKey = errors.DefineInvalidArgument(
+		"invalid_attribute_key",
+		"key `{key}` is not a valid attribute key",
+	)
+	errAttributeValueTooLong = errors.DefineInvalidArgument(
+		"attribute_value_too_long",
+		"value of `{key}` is longer than {max} characters",
+	)
+	errAttributeConflict = errors.DefineAlreadyExists(
+		"attribute_conflict",
+		"key `{key}` and `{other_key}` are both set as attribute `{attribute}`",
+	)
+)
+
+// Attributes are the attributes of an entity on The Things Stack. The keys of the attributes are
+// derived from other keys with Slug.
+type Attributes struct {
+	values map[string]string
+	// keys contains the original keys by attribute key.
+	keys map[string]string
+}
+
+// Set sets the attribute of the key. The attribute is not set, and an error is returned, if the key
+// is not a valid attribute key, if the value is too long, or if another key has the same attribute key.
+func (a *Attributes) Set(key, value string) error {
+	attrKey := Slug(key)
+	if !idPattern.MatchString(attrKey) {
+		return errInvalidAttributeKey.WithAttributes("key", key)
+	}
+	if len(value) > maxAttributeValueLength {
+		return errAttributeValueTooLong.WithAttributes("key", key, "max", maxAttributeValueLength)
+	}
+	if other, ok := a.keys[attrKey]; ok && other != key {
+		return errAttributeConflict.WithAttributes("key", key, "other_key", other, "attribute", attrKey)
+	}
+	if a.values == nil {
+		a.values = make(map[string]string)
+		a.keys = make(map[string]string)
+	}
+	a.values[attrKey] = value
+	a.keys[attrKey] = key
+	return nil
+}
+
+// Map returns the attributes, or nil if no attributes are set.
+func (a *Attributes) Map() map[string]string {
+	return a.values
+}
@@ -0,0 +1,48 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+func TestAttributes(t *testing.T) {
+	a := assertions.New(t)
+	var attrs util.Attributes
+	a.So(attrs.Map(), should.BeNil)
+
+	a.So(attrs.Set("Site Name", "berlin"), should.BeNil)
+	a.So(attrs.Set("floor", "2"), should.BeNil)
+	// Keys that are too short.
+	a.So(attrs.Set("id", "1"), should.NotBeNil)
+	a.So(attrs.Set("!!!", "1"), should.NotBeNil)
+	// Values that are too long.
+	a.So(attrs.Set("description", strings.Repeat("a", 201)), should.NotBeNil)
+	a.So(attrs.Set("comment", strings.Repeat("a", 200)), should.BeNil)
+	// Keys with the same attribute key.
+	a.So(attrs.Set("site_name", "paris"), should.NotBeNil)
+	a.So(attrs.Set("Site Name", "amsterdam"), should.BeNil)
+
+	a.So(attrs.Map(), should.Resemble, map[string]string{
+		"site-name": "amsterdam",
+		"floor":     "2",
+		"comment":   strings.Repeat("a", 200),
+	})
+}
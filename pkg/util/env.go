@@ -0,0 +1,53 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// EnvString returns the value of the environment variable, or def if it is not set.
+func EnvString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// EnvDuration returns the duration of the environment variable, or def if it is not set or invalid.
+func EnvDuration(key string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return d
+	}
+	return def
+}
+
+// EnvUint returns the unsigned integer of the environment variable, or def if it is not set or invalid.
+func EnvUint(key string, def uint) uint {
+	if v, err := strconv.ParseUint(os.Getenv(key), 10, 0); err == nil {
+		return uint(v)
+	}
+	return def
+}
+
+// EnvFloat returns the floating point number of the environment variable, or def if it is not set or invalid.
+func EnvFloat(key string, def float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return def
+}
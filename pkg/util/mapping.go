@@ -0,0 +1,129 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bufio"
+	"encoding/csv"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.thethings.network/lorawan-stack/v3/pkg/errors"
+)
+
+// maxIDLength is the maximum length of an identifier on The Things Stack.
+const maxIDLength = 36
+
+// idPattern is the pattern of identifiers on The Things Stack.
+var idPattern = regexp.MustCompile(`^[a-z0-9](?:[-]?[a-z0-9]){2,}$`)
+
+var (
+	errReadMappingFile = errors.DefineInvalidArgument("read_mapping_file", "read mapping file `{file}`")
+	errInvalidMapping  = errors.DefineInvalidArgument("invalid_mapping", "invalid mapping on line {line} of `{file}`")
+	errInvalidMappedID = errors.DefineInvalidArgument("invalid_mapped_id", "invalid ID on line {line} of `{file}`")
+	errInvalidID       = errors.DefineInvalidArgument(
+		"invalid_id",
+		"`{id}` is not a valid ID, IDs must have 3 to 36 lowercase letters, digits or single dashes",
+	)
+)
+
+// ReadMappingFile reads a mapping file. Each non-empty line contains a key and a value, separated by
+// a comma. Keys may contain spaces, and keys with commas are quoted, like in CSV files. Lines without
+// a comma contain a key and a value separated by whitespace. Lines starting with `#` are ignored.
+// The values must be valid identifiers on The Things Stack.
+func ReadMappingFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errReadMappingFile.WithAttributes("file", path).WithCause(err)
+	}
+	defer f.Close()
+
+	mapping := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		key, value, ok := parseMapping(s)
+		if !ok {
+			return nil, errInvalidMapping.WithAttributes("file", path, "line", line)
+		}
+		if err := ValidateID(value); err != nil {
+			return nil, errInvalidMappedID.WithAttributes("file", path, "line", line).WithCause(err)
+		}
+		mapping[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errReadMappingFile.WithAttributes("file", path).WithCause(err)
+	}
+	return mapping, nil
+}
+
+// parseMapping returns the key and value of a line of a mapping file.
+func parseMapping(s string) (key, value string, ok bool) {
+	var fields []string
+	if strings.Contains(s, ",") {
+		r := csv.NewReader(strings.NewReader(s))
+		r.TrimLeadingSpace = true
+		record, err := r.Read()
+		if err != nil {
+			return "", "", false
+		}
+		fields = record
+	} else {
+		fields = strings.Fields(s)
+	}
+	if len(fields) != 2 {
+		return "", "", false
+	}
+	key, value = strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+	return key, value, key != "" && value != ""
+}
+
+// ValidateID returns an error if id is not a valid identifier on The Things Stack.
+func ValidateID(id string) error {
+	if len(id) > maxIDLength || !idPattern.MatchString(id) {
+		return errInvalidID.WithAttributes("id", id)
+	}
+	return nil
+}
+
+// Slug converts s to an identifier on The Things Stack. Characters that are not allowed are
+// replaced by dashes, and the result is truncated to the maximum identifier length.
+// An empty string is returned if s contains no alphanumeric characters. The result may be
+// shorter than the minimum identifier length, so it must be validated with ValidateID.
+func Slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	slug := b.String()
+	if len(slug) > maxIDLength {
+		slug = strings.TrimRight(slug[:maxIDLength], "-")
+	}
+	return slug
+}
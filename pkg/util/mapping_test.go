@@ -0,0 +1,126 @@
+// Copyright © 2026 The Things Network Foundation, The Things Industries B.V.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smarty/assertions"
+	"github.com/smarty/assertions/should"
+	"go.thethings.network/lorawan-stack-migrate/pkg/util"
+)
+
+func TestReadMappingFile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		content  string
+		expected map[string]string
+		invalid  bool
+	}{
+		{
+			name: "Valid",
+			content: `# key, value
+0001,building-a
+Smart Building,building-b
+My App, my-app
+"Sensors, outdoor",outdoor
+tag:water	water-meters
+organization:1  org-1
+`,
+			expected: map[string]string{
+				"0001":             "building-a",
+				"Smart Building":   "building-b",
+				"My App":           "my-app",
+				"Sensors, outdoor": "outdoor",
+				"tag:water":        "water-meters",
+				"organization:1":   "org-1",
+			},
+		},
+		{
+			name:    "TooManyFields",
+			content: "My App,my-app,other\n",
+			invalid: true,
+		},
+		{
+			name:    "NoValue",
+			content: "My App,\n",
+			invalid: true,
+		},
+		{
+			name:    "InvalidID",
+			content: "My App,a\n",
+			invalid: true,
+		},
+		{
+			name:    "SpacesWithoutComma",
+			content: "My App my-app\n",
+			invalid: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assertions.New(t)
+			path := filepath.Join(t.TempDir(), "mapping.csv")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			mapping, err := util.ReadMappingFile(path)
+			if tc.invalid {
+				a.So(err, should.NotBeNil)
+				return
+			}
+			a.So(err, should.BeNil)
+			a.So(mapping, should.Resemble, tc.expected)
+		})
+	}
+}
+
+func TestSlug(t *testing.T) {
+	a := assertions.New(t)
+	a.So(util.Slug("Smart Building"), should.Equal, "smart-building")
+	a.So(util.Slug("  Water_Meters #2 "), should.Equal, "water-meters-2")
+	a.So(util.Slug("!!!"), should.Equal, "")
+	a.So(util.Slug("A"), should.Equal, "a")
+}
+
+func TestValidateID(t *testing.T) {
+	for _, tc := range []struct {
+		id    string
+		valid bool
+	}{
+		{id: "my-app", valid: true},
+		{id: "app", valid: true},
+		{id: "123456789012345678901234567890123456", valid: true},
+		{id: ""},
+		{id: "a"},
+		{id: "ab"},
+		{id: "My-App"},
+		{id: "my--app"},
+		{id: "-my-app"},
+		{id: "my-app-"},
+		{id: "1234567890123456789012345678901234567"},
+	} {
+		t.Run(tc.id, func(t *testing.T) {
+			a := assertions.New(t)
+			err := util.ValidateID(tc.id)
+			if tc.valid {
+				a.So(err, should.BeNil)
+			} else {
+				a.So(err, should.NotBeNil)
+			}
+		})
+	}
+}